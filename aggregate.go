@@ -0,0 +1,158 @@
+// aggregate.go - numeric aggregations of the values for a path.
+
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var aggregateStrict bool
+
+// AggregateStrict causes SumForPath(), AvgForPath(), MinForPath() and MaxForPath() to
+// return an error if a value for the path is not numeric. By default such values are
+// skipped. If called with no argument, the flag is toggled.
+func AggregateStrict(b ...bool) {
+	if len(b) == 0 {
+		aggregateStrict = !aggregateStrict
+	} else if len(b) == 1 {
+		aggregateStrict = b[0]
+	}
+}
+
+// SumForPath returns the sum of the numeric values for 'path'. The 'path' is as for
+// ValuesForPath() and can contain wildcards and indexed array references.
+// Numeric values are float64, int, etc., and json.Number values as well as string values
+// that parse as float64 - so it doesn't matter if NewMapXml() was called with 'cast' or
+// not; the "#text" value of a simple element with attributes is used.
+// If there are no values the sum is 0.
+func (mv Map) SumForPath(path string) (float64, error) {
+	f, err := mv.numbersForPath(path)
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for _, v := range f {
+		sum += v
+	}
+	return sum, nil
+}
+
+// AvgForPath returns the average of the numeric values for 'path'.
+// See SumForPath(). Error is returned if there are no numeric values.
+func (mv Map) AvgForPath(path string) (float64, error) {
+	f, err := mv.numbersForPath(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(f) == 0 {
+		return 0, fmt.Errorf("no numeric values for path: %s", path)
+	}
+	var sum float64
+	for _, v := range f {
+		sum += v
+	}
+	return sum / float64(len(f)), nil
+}
+
+// MinForPath returns the minimum of the numeric values for 'path'.
+// See SumForPath(). Error is returned if there are no numeric values.
+func (mv Map) MinForPath(path string) (float64, error) {
+	f, err := mv.numbersForPath(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(f) == 0 {
+		return 0, fmt.Errorf("no numeric values for path: %s", path)
+	}
+	min := f[0]
+	for _, v := range f[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min, nil
+}
+
+// MaxForPath returns the maximum of the numeric values for 'path'.
+// See SumForPath(). Error is returned if there are no numeric values.
+func (mv Map) MaxForPath(path string) (float64, error) {
+	f, err := mv.numbersForPath(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(f) == 0 {
+		return 0, fmt.Errorf("no numeric values for path: %s", path)
+	}
+	max := f[0]
+	for _, v := range f[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max, nil
+}
+
+// numbersForPath returns the numeric values for 'path' - per AggregateStrict().
+func (mv Map) numbersForPath(path string) ([]float64, error) {
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	f := make([]float64, 0, len(vals))
+	for i, v := range vals {
+		if m, ok := v.(map[string]interface{}); ok {
+			if t, ok := m["#text"]; ok {
+				v = t
+			}
+		}
+		n, ok := toFloat64(v)
+		if !ok {
+			if aggregateStrict {
+				return nil, fmt.Errorf("value #%d for path %s is not numeric: %v", i, path, v)
+			}
+			continue
+		}
+		f = append(f, n)
+	}
+	return f, nil
+}
+
+// toFloat64 converts numeric and numeric string values to float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch v.(type) {
+	case float64:
+		return v.(float64), true
+	case float32:
+		return float64(v.(float32)), true
+	case int:
+		return float64(v.(int)), true
+	case int8:
+		return float64(v.(int8)), true
+	case int16:
+		return float64(v.(int16)), true
+	case int32:
+		return float64(v.(int32)), true
+	case int64:
+		return float64(v.(int64)), true
+	case uint:
+		return float64(v.(uint)), true
+	case uint8:
+		return float64(v.(uint8)), true
+	case uint16:
+		return float64(v.(uint16)), true
+	case uint32:
+		return float64(v.(uint32)), true
+	case uint64:
+		return float64(v.(uint64)), true
+	case json.Number:
+		f, err := v.(json.Number).Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.(string)), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
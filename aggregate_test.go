@@ -0,0 +1,45 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAggregateForPath(t *testing.T) {
+	fmt.Println("\n------------ aggregate_test.go")
+	m, err := NewMapXml([]byte(`<orders><order><total>10.5</total></order><order><total currency="USD">4.5</total></order><order><total>n/a</total></order><order><total>30</total></order></orders>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "orders.order.total"
+	for name, fn := range map[string]func(string) (float64, error){
+		"sum": m.SumForPath, "avg": m.AvgForPath, "min": m.MinForPath, "max": m.MaxForPath,
+	} {
+		want := map[string]float64{"sum": 45, "avg": 15, "min": 4.5, "max": 30}[name]
+		v, err := fn(path)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if v != want {
+			t.Fatal(name, "got:", v, "want:", want)
+		}
+	}
+
+	m, _ = NewMapXml([]byte(`<doc><a>1</a><b>2</b></doc>`), true)
+	if v, err := m.SumForPath("doc.*"); err != nil || v != 3 {
+		t.Fatal("doc.* sum:", v, err)
+	}
+	if v, err := m.SumForPath("doc.none"); err != nil || v != 0 {
+		t.Fatal("doc.none sum:", v, err)
+	}
+	if _, err := m.AvgForPath("doc.none"); err == nil {
+		t.Fatal("no error for avg of no values")
+	}
+
+	AggregateStrict(true)
+	defer AggregateStrict(false)
+	m, _ = NewMapXml([]byte(`<doc><a>1</a><a>x</a></doc>`))
+	if _, err := m.SumForPath("doc.a"); err == nil {
+		t.Fatal("no error for non-numeric value")
+	}
+}
@@ -0,0 +1,58 @@
+// ancestors.go - the ancestry of a node identified by a concrete path.
+
+package mxj
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Ancestors returns the keys of the ancestors of the node at the concrete 'path', from
+// the root to the node's parent, with list members subscripted - e.g., for
+// "doc.books.book[1].title" it returns ["doc", "books", "book[1]"]; joined with "." the
+// keys are the paths of the ancestors. The 'path' is as returned by mv.FirstMatch() - it
+// has no wildcards and every list on the path is indexed, though a value that is not a
+// list can be indexed as member [0]. A top level key has no ancestors.
+// PathNotExistError is returned if there is no node for 'path'; error is also returned
+// if 'path' has a wildcard or a list on it is not indexed.
+func (mv Map) Ancestors(path string) ([]string, error) {
+	keys, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, PathNotExistError
+	}
+	ancestors := make([]string, 0, len(keys)-1)
+	var v interface{} = map[string]interface{}(mv)
+	for i, k := range keys {
+		if k.name == "*" {
+			return nil, fmt.Errorf("Ancestors: path has a wildcard: %s", path)
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, PathNotExistError
+		}
+		if v, ok = m[k.name]; !ok {
+			return nil, PathNotExistError
+		}
+		name := k.name
+		if k.isArray {
+			if a, ok := v.([]interface{}); ok {
+				if k.position >= len(a) {
+					return nil, PathNotExistError
+				}
+				v = a[k.position]
+			} else if k.position != 0 {
+				return nil, PathNotExistError
+			}
+			name += "[" + strconv.Itoa(k.position) + "]"
+		} else if _, ok := v.([]interface{}); ok && i < len(keys)-1 {
+			return nil, fmt.Errorf("Ancestors: list is not indexed: %s", k.name)
+		}
+		if i < len(keys)-1 {
+			ancestors = append(ancestors, name)
+		}
+	}
+	return ancestors, nil
+}
@@ -0,0 +1,58 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAncestors(t *testing.T) {
+	fmt.Println("\n------------ ancestors_test.go")
+	m := Map{"doc": map[string]interface{}{
+		"books": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "a"},
+				map[string]interface{}{"title": "b"},
+			},
+		},
+		"one": map[string]interface{}{"title": "c"},
+	}}
+
+	path, _, err := m.FirstMatch("doc.*.book.title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := m.Ancestors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(a) != "[doc books book[0]]" {
+		t.Fatal("got:", a)
+	}
+
+	checks := map[string]string{
+		"doc":                     "[]",
+		"doc.books.book":          "[doc books]",
+		"doc.books.book[1].title": "[doc books book[1]]",
+		"doc.one[0].title":        "[doc one[0]]",
+	}
+	for p, want := range checks {
+		a, err := m.Ancestors(p)
+		if err != nil {
+			t.Fatal(p, err)
+		}
+		if fmt.Sprint(a) != want {
+			t.Fatal(p, "got:", a, "want:", want)
+		}
+	}
+
+	for _, p := range []string{"doc.none", "doc.books.book[2]", "doc.one[1]", "doc.one.title.x", ""} {
+		if _, err = m.Ancestors(p); err != PathNotExistError {
+			t.Fatal(p, "err:", err)
+		}
+	}
+	for _, p := range []string{"doc.*.book[0]", "doc.books.book.title"} {
+		if _, err = m.Ancestors(p); err == nil || err == PathNotExistError {
+			t.Fatal(p, "err:", err)
+		}
+	}
+}
@@ -153,6 +153,7 @@ func AnyXmlIndent(v interface{}, prefix, indent string, tags ...string) ([]byte,
 	p := new(pretty)
 	p.indent = indent
 	p.padding = prefix
+	p.maxLineWidth = xmlIndentMaxLineWidth
 
 	var b []byte
 	switch v.(type) {
@@ -0,0 +1,40 @@
+// apply.go - replace all the values for a key.
+
+package mxj
+
+// Apply replaces each value for 'key', at any depth of the Map, with the result of
+// calling 'fn' with the value and returns the number of values replaced. If the value
+// for 'key' is a list - e.g., a repeated XML element - 'fn' is applied to each member.
+// Values nested within a value for 'key' are handled before 'fn' is called for it.
+// For attributes prefix 'key' with the attribute prefix, e.g., "-country".
+func (mv Map) Apply(key string, fn func(interface{}) interface{}) int {
+	return applyValue(map[string]interface{}(mv), key, fn)
+}
+
+func applyValue(v interface{}, key string, fn func(interface{}) interface{}) int {
+	var n int
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		for k, vv := range m {
+			n += applyValue(vv, key, fn)
+			if k != key {
+				continue
+			}
+			if list, ok := vv.([]interface{}); ok {
+				for i, lv := range list {
+					list[i] = fn(lv)
+				}
+				n += len(list)
+				continue
+			}
+			m[k] = fn(vv)
+			n++
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			n += applyValue(vv, key, fn)
+		}
+	}
+	return n
+}
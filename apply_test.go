@@ -0,0 +1,39 @@
+package mxj
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	fmt.Println("\n------------ apply_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc><addr country="us"><country>us</country></addr><list><addr><country>ca</country><country>mx</country></addr></list></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper := func(v interface{}) interface{} {
+		if s, ok := v.(string); ok {
+			return strings.ToUpper(s)
+		}
+		return v
+	}
+
+	if n := m.Apply("country", upper); n != 3 {
+		t.Fatal("replaced:", n)
+	}
+	if n := m.Apply("-country", upper); n != 1 {
+		t.Fatal("replaced attributes:", n)
+	}
+	x, _ := m.Xml()
+	want := `<doc><addr country="US"><country>US</country></addr><list><addr><country>CA</country><country>MX</country></addr></list></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if n := m.Apply("none", upper); n != 0 {
+		t.Fatal("replaced none:", n)
+	}
+}
@@ -0,0 +1,88 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDecodeAttrsAsMap(t *testing.T) {
+	fmt.Println("\n------------ attrmap_test.go")
+	DecodeAttrsAsMap(true)
+	defer DecodeAttrsAsMap(false)
+
+	data := []byte(`<doc><elem type="attr" seq="1"><type>elem</type></elem><simple id="2">text</simple></doc>`)
+	m, err := NewMapXml(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := m.ValueForPath("doc.elem.#attr.type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "attr" {
+		t.Fatal("doc.elem.#attr.type:", v)
+	}
+	v, err = m.ValueForPath("doc.elem.#attr.seq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(float64) != 1 {
+		t.Fatal("doc.elem.#attr.seq:", v)
+	}
+	v, err = m.ValueForPath("doc.elem.type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "elem" {
+		t.Fatal("doc.elem.type:", v)
+	}
+	v, err = m.ValueForPath("doc.simple.#text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "text" {
+		t.Fatal("doc.simple.#text:", v)
+	}
+	if _, err = m.ValueForPath("doc.elem.-type"); err == nil {
+		t.Fatal("found prefixed attribute key")
+	}
+}
+
+func TestEncodeAttrsMap(t *testing.T) {
+	PrependAttrWithHyphen(true)
+
+	m := Map{"doc": map[string]interface{}{
+		"#attr": map[string]interface{}{"id": 1, "type": "a&b"},
+		"-seq":  "2",
+		"elem":  map[string]interface{}{"#attr": map[string]interface{}{"x": "y"}, "#text": "text"},
+		"empty": map[string]interface{}{"#attr": map[string]interface{}{"x": "y"}},
+	}}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc id="1" seq="2" type="a&b"><elem x="y">text</elem><empty x="y"/></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// round trip
+	data := []byte(`<doc><elem seq="1" type="attr"><type>elem</type></elem></doc>`)
+	DecodeAttrsAsMap(true)
+	m, err = NewMapXml(data)
+	DecodeAttrsAsMap(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, err = m.Xml(); err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != string(data) {
+		t.Fatal("got:", string(x), "want:", string(data))
+	}
+
+	m = Map{"doc": map[string]interface{}{"#attr": map[string]interface{}{"bad": []int{1}}}}
+	if _, err = m.Xml(); err == nil {
+		t.Fatal("no error for invalid attribute value")
+	}
+}
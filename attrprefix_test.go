@@ -147,3 +147,44 @@ func TestMarshalPrefixUnderscore(t *testing.T) {
 	fmt.Println(string(x))
 }
 
+func TestSetAttrPrefixRoundTrip(t *testing.T) {
+	defer SetAttrPrefix("-")
+	if err := SetAttrPrefix("@"); err != nil {
+		t.Fatal(err)
+	}
+	if p := AttrPrefix(); p != "@" {
+		t.Fatal("AttrPrefix:", p)
+	}
+
+	src := []byte(`<doc id="1"><elem type="a">text</elem></doc>`)
+	m, err := NewMapXml(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := m.Json()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"doc":{"@id":"1","elem":{"#text":"text","@type":"a"}}}`; string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+	if m, err = NewMapJson(j); err != nil {
+		t.Fatal(err)
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != string(src) {
+		t.Fatal("got:", string(x), "want:", string(src))
+	}
+
+	for _, p := range []string{"", "a.b", "[", "x]", "#"} {
+		if err = SetAttrPrefix(p); err == nil {
+			t.Fatal("no error for:", p)
+		}
+	}
+	if p := AttrPrefix(); p != "@" {
+		t.Fatal("prefix changed:", p)
+	}
+}
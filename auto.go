@@ -0,0 +1,27 @@
+// auto.go - decode a Map from either XML or JSON.
+
+package mxj
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// NewMapAuto decodes 'data' as XML or JSON, based on the first character that is
+// not white space or a UTF-8 BOM:
+//	'<'      - XML, as with NewMapXml(data, cast...).
+//	'{', '[' - JSON, as with NewMapJson(data); 'cast' is ignored.
+// Anything else is an error. Empty or all white space 'data' begets an empty Map.
+func NewMapAuto(data []byte, cast ...bool) (Map, error) {
+	b := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(b) == 0 {
+		return make(map[string]interface{}, 0), nil
+	}
+	switch b[0] {
+	case '<':
+		return NewMapXml(data, cast...)
+	case '{', '[':
+		return NewMapJson(b)
+	}
+	return nil, fmt.Errorf("NewMapAuto: not XML or JSON, starts with: %q", b[0])
+}
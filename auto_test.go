@@ -0,0 +1,41 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewMapAuto(t *testing.T) {
+	fmt.Println("\n------------ auto_test.go")
+
+	m, err := NewMapAuto([]byte("\n  <doc><a>1</a></doc>"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.a"); v != float64(1) {
+		t.Fatal("xml doc.a:", v)
+	}
+
+	m, err = NewMapAuto([]byte("\xef\xbb\xbf {\"doc\":{\"a\":1}}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.a"); v != float64(1) {
+		t.Fatal("json doc.a:", v)
+	}
+
+	m, err = NewMapAuto([]byte(" [1,2]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValuesForPath("object"); len(v) != 2 {
+		t.Fatal("json list:", v)
+	}
+
+	if m, err = NewMapAuto([]byte(" \n")); err != nil || len(m) != 0 {
+		t.Fatal("empty:", m, err)
+	}
+	if _, err = NewMapAuto([]byte("a=1")); err == nil {
+		t.Fatal("no error for unknown format")
+	}
+}
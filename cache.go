@@ -0,0 +1,109 @@
+// cache.go - memoize mv.Xml() output by Map content.
+
+package mxj
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// XmlCacheSize is the maximum number of serializations retained by Map.XmlCached.
+// When the cache is full it is cleared before the next value is added.
+var XmlCacheSize = 1024
+
+var xmlCache = struct {
+	sync.RWMutex
+	m map[[sha256.Size]byte][]byte
+}{m: make(map[[sha256.Size]byte][]byte)}
+
+// XmlCached returns the same encoding as mv.Xml(rootTag...), but reuses the result
+// of a previous call if the Map content and 'rootTag' are unchanged.
+// The cache is keyed by a hash of the Map content, so it is safe to modify the Map
+// between calls; the hash is computed on every call. XmlCached is safe for concurrent
+// use as long as the Map is not being modified while it is called.
+//	NOTE: the cache does not track the encoder settings - e.g., XMLEscapeChars(),
+//	      PrependAttrWithHyphen(); call ClearXmlCache() after changing them.
+func (mv Map) XmlCached(rootTag ...string) ([]byte, error) {
+	key := mv.checksum(rootTag...)
+
+	xmlCache.RLock()
+	b, ok := xmlCache.m[key]
+	xmlCache.RUnlock()
+	if ok {
+		return append([]byte(nil), b...), nil
+	}
+
+	b, err := mv.Xml(rootTag...)
+	if err != nil {
+		return nil, err
+	}
+	xmlCache.Lock()
+	if len(xmlCache.m) >= XmlCacheSize {
+		xmlCache.m = make(map[[sha256.Size]byte][]byte)
+	}
+	xmlCache.m[key] = b
+	xmlCache.Unlock()
+	return append([]byte(nil), b...), nil
+}
+
+// ClearXmlCache discards all serializations retained by Map.XmlCached.
+func ClearXmlCache() {
+	xmlCache.Lock()
+	xmlCache.m = make(map[[sha256.Size]byte][]byte)
+	xmlCache.Unlock()
+}
+
+// checksum returns a hash of the Map content and 'rootTag'.
+func (mv Map) checksum(rootTag ...string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, r := range rootTag {
+		writeHashString(h, r)
+	}
+	hashValue(h, map[string]interface{}(mv))
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// hashValue writes a type-tagged representation of 'v' to 'h'; map keys are sorted.
+func hashValue(h hash.Hash, v interface{}) {
+	switch v := v.(type) {
+	case Map:
+		hashValue(h, map[string]interface{}(v))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		h.Write([]byte{'m'})
+		writeHashString(h, strconv.Itoa(len(keys)))
+		for _, k := range keys {
+			writeHashString(h, k)
+			hashValue(h, v[k])
+		}
+	case []interface{}:
+		h.Write([]byte{'l'})
+		writeHashString(h, strconv.Itoa(len(v)))
+		for _, vv := range v {
+			hashValue(h, vv)
+		}
+	case string:
+		h.Write([]byte{'s'})
+		writeHashString(h, v)
+	default:
+		h.Write([]byte{'v'})
+		writeHashString(h, fmt.Sprintf("%T:%v", v, v))
+	}
+}
+
+// writeHashString writes the length-prefixed string 's' to 'h'.
+func writeHashString(h hash.Hash, s string) {
+	h.Write([]byte(strconv.Itoa(len(s))))
+	h.Write([]byte{':'})
+	h.Write([]byte(s))
+}
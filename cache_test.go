@@ -0,0 +1,65 @@
+package mxj
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestXmlCached(t *testing.T) {
+	fmt.Println("\n------------ cache_test.go")
+	ClearXmlCache()
+	defer ClearXmlCache()
+
+	m := Map{"doc": map[string]interface{}{"a": "1", "b": []interface{}{"x", "y"}}}
+	want, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x, err := m.XmlCached()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(x) != string(want) {
+				t.Error("got:", string(x), "want:", string(want))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// modified content is not served from the cache
+	m["doc"].(map[string]interface{})["a"] = 1
+	x, err := m.XmlCached()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != `<doc><a>1</a><b>x</b><b>y</b></doc>` {
+		t.Fatal("got:", string(x))
+	}
+	// same string value as before, different type
+	if m.checksum() == (Map{"doc": map[string]interface{}{"a": "1", "b": []interface{}{"x", "y"}}}).checksum() {
+		t.Fatal("checksum ignores value type")
+	}
+
+	// rootTag is part of the key
+	x, err = m.XmlCached("root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != `<root><doc><a>1</a><b>x</b><b>y</b></doc></root>` {
+		t.Fatal("got:", string(x))
+	}
+
+	// returned values are copies
+	x[1] = 'X'
+	if x, _ = m.XmlCached(); string(x) != `<doc><a>1</a><b>x</b><b>y</b></doc>` {
+		t.Fatal("cache modified:", string(x))
+	}
+}
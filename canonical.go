@@ -0,0 +1,56 @@
+// canonical.go - encode a Map as canonical XML for reproducible output.
+
+package mxj
+
+import "strings"
+
+// XmlCanonical encodes the Map as XML, as with mv.Xml(rootTag...), in a canonical form
+// that is the same for the same Map content - e.g., so that a signature of the XML is
+// stable. It follows the W3C Canonical XML (c14n) rules for serialization, but does not
+// implement c14n processing of a source document - e.g., superfluous name space
+// declarations are not removed:
+//	- there is no XML declaration, comments or white space between elements;
+//	- elements are sorted by key and empty elements have start and end tags, <a></a>;
+//	- attribute values are quoted with '"'; the name space declarations, "xmlns" then
+//	  "xmlns:prefix" by prefix, precede the other attributes, which are sorted by name;
+//	- text is escaped as '&amp;', '&lt;', '&gt;' and '&#xD;', and attribute values as
+//	  '&amp;', '&lt;', '&quot;', '&#x9;', '&#xA;' and '&#xD;'.
+// The package encoding settings - XMLEscapeChars(), XmlGoEmptyElemSyntax(),
+// XmlEmitSourceComments, etc. - are not used. Error is returned if the Map does not encode
+// as a well-formed UTF-8 XML document with a single root element - see mv.XmlValidate();
+// values are always escaped, so they can have '<' and '&'.
+//	NOTE: with XMLEscapeCharsDecoder(true) the decoded values are already escaped and
+//	      are escaped again.
+func (mv Map) XmlCanonical(rootTag ...string) ([]byte, error) {
+	if err := mv.xmlValidate(true, rootTag...); err != nil {
+		return nil, err
+	}
+	p := new(pretty)
+	p.opts = &EncodeOptions{EscapeChars: true, EscapeMode: escapeCanonical, GoEmptyElemSyntax: true}
+	p.canonical = true
+	return mv.xml(p, rootTag...)
+}
+
+// canonicalAttrList sorts attributes in c14n order - name space declarations first.
+type canonicalAttrList [][2]string
+
+func (a canonicalAttrList) Len() int {
+	return len(a)
+}
+
+func (a canonicalAttrList) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+
+func (a canonicalAttrList) Less(i, j int) bool {
+	ni, nj := isNSDecl(a[i][0]), isNSDecl(a[j][0])
+	if ni != nj {
+		return ni
+	}
+	return a[i][0] < a[j][0] // "xmlns" sorts before "xmlns:prefix"
+}
+
+// isNSDecl reports whether the attribute 'name' is a name space declaration.
+func isNSDecl(name string) bool {
+	return name == "xmlns" || strings.HasPrefix(name, "xmlns:")
+}
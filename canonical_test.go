@@ -0,0 +1,52 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestXmlCanonical(t *testing.T) {
+	fmt.Println("\n------------ canonical_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<?xml version="1.0"?>
+<doc z="1" xmlns:b="urn:b" a='x"y' xmlns="urn:d" xmlns:a="urn:a">
+	<!-- comment -->
+	<b:item>a &amp; b</b:item>
+	<empty/>
+	<attrs tab="a&#9;b"/>
+</doc>`)
+	PreserveNamespacePrefixes(true)
+	m, err := NewMapXml(data)
+	PreserveNamespacePrefixes(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := m.XmlCanonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc xmlns="urn:d" xmlns:a="urn:a" xmlns:b="urn:b" a="x&quot;y" z="1">` +
+		`<attrs tab="a&#x9;b"></attrs><b:item>a &amp; b</b:item><empty></empty></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// the same for the same content
+	c, _ := m.Copy()
+	if x2, _ := c.XmlCanonical(); string(x2) != string(x) {
+		t.Fatal("got:", string(x2), "want:", string(x))
+	}
+
+	m = Map{"a": "1", "b": "2"}
+	if _, err = m.XmlCanonical(); err == nil {
+		t.Fatal("no error for multiple roots")
+	}
+	if x, err = m.XmlCanonical("doc"); err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != `<doc><a>1</a><b>2</b></doc>` {
+		t.Fatal("got:", string(x))
+	}
+}
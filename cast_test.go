@@ -1,6 +1,8 @@
 package mxj
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 )
@@ -78,3 +80,65 @@ func TestCastValuesToBool(t *testing.T) {
 	}
 	fmt.Printf("%#v\n", m)
 }
+
+func TestCastValuesToBoolStrict(t *testing.T) {
+	fmt.Println("------------ TestCastValuesToBoolStrict(true) ...")
+	data := []byte(`<doc><a>true</a><b>false</b><c>t</c><d>TRUE</d><e>False</e><f>1</f></doc>`)
+
+	m, err := NewMapXml(data, true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if v, _ := m.ValueForPath("doc.c"); v != true {
+		t.Fatal("default doc.c:", v)
+	}
+
+	CastValuesToBoolStrict(true)
+	defer CastValuesToBoolStrict(false)
+	if m, err = NewMapXml(data, true); err != nil {
+		t.Fatal(err.Error())
+	}
+	fmt.Printf("%#v\n", m)
+	for path, want := range map[string]interface{}{
+		"doc.a": true,
+		"doc.b": false,
+		"doc.c": "t",
+		"doc.d": "TRUE",
+		"doc.e": "False",
+		"doc.f": float64(1),
+	} {
+		if v, _ := m.ValueForPath(path); v != want {
+			t.Fatalf("%s: got: %#v want: %#v", path, v, want)
+		}
+	}
+}
+
+func TestRecastAsJsonNumber(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	RecastAsJsonNumber = true
+	defer func() { RecastAsJsonNumber = false }()
+
+	data := []byte(`<doc id="007"><price>1.50</price><big>12345678901234567890</big><plus>+1</plus><flag>true</flag></doc>`)
+	m, err := NewMapXml(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := m.Json()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"doc":{"-id":7,"big":12345678901234567890,"flag":true,"plus":1,"price":1.50}}`
+	if string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+	if v, _ := m.ValueForPath("doc.price"); v != json.Number("1.50") {
+		t.Fatalf("doc.price: %#v", v)
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(x, []byte(`<price>1.50</price>`)) {
+		t.Fatal("xml:", string(x))
+	}
+}
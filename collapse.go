@@ -0,0 +1,41 @@
+// collapse.go - merge a list of single key maps into a map.
+
+package mxj
+
+import "fmt"
+
+// CollapseArrayToMap replaces the list, []interface{}, value for 'path' with a
+// map[string]interface{} that has the key:value pair of each list member, which
+// must be a map with a single key, and returns the number of members merged. Thus
+//	{"opts":[{"a":1},{"b":2}]}
+// with mv.CollapseArrayToMap("opts") becomes {"opts":{"a":1,"b":2}}.
+// Error is returned, and the Map is not modified, if a member is not a single key
+// map or if two members have the same key.
+//	NOTE: 'path' must be a dot-separated list of keys; indexed references are not supported.
+func (mv Map) CollapseArrayToMap(path string) (int, error) {
+	pm, err := prevValueByPath(map[string]interface{}(mv), path)
+	if err != nil {
+		return 0, err
+	}
+	key := lastKey(path)
+	list, ok := pm[key].([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("CollapseArrayToMap: value for path is not a list: %s", path)
+	}
+
+	m := make(map[string]interface{}, len(list))
+	for i, v := range list {
+		vm, ok := v.(map[string]interface{})
+		if !ok || len(vm) != 1 {
+			return 0, fmt.Errorf("CollapseArrayToMap: member #%d for path %s is not a single key map", i, path)
+		}
+		for k, vv := range vm {
+			if _, ok := m[k]; ok {
+				return 0, fmt.Errorf("CollapseArrayToMap: key %s for path %s is not unique", k, path)
+			}
+			m[k] = vv
+		}
+	}
+	pm[key] = m
+	return len(list), nil
+}
@@ -0,0 +1,39 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCollapseArrayToMap(t *testing.T) {
+	fmt.Println("\n------------ collapse_test.go")
+
+	m, err := NewMapJson([]byte(`{"doc":{"opts":[{"a":1},{"b":{"c":2}}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := m.CollapseArrayToMap("doc.opts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatal("merged:", n)
+	}
+	j, _ := m.Json()
+	if want := `{"doc":{"opts":{"a":1,"b":{"c":2}}}}`; string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+
+	for _, v := range []string{`{"opts":[{"a":1},{"a":2}]}`, `{"opts":[{"a":1},{"b":2,"c":3}]}`, `{"opts":[{"a":1},"b"]}`, `{"opts":"a"}`} {
+		m, _ = NewMapJson([]byte(v))
+		if _, err = m.CollapseArrayToMap("opts"); err == nil {
+			t.Fatal("no error for:", v)
+		}
+		if j, _ = m.Json(); string(j) != v {
+			t.Fatal("modified on error:", string(j))
+		}
+	}
+	if _, err = m.CollapseArrayToMap("none"); err == nil {
+		t.Fatal("no error for missing path")
+	}
+}
@@ -0,0 +1,77 @@
+// compact.go - remove the empty members of the lists in a Map.
+
+package mxj
+
+// CompactArrays removes the nil, empty string and empty map[string]interface{} members
+// from every list, []interface{}, in the Map - e.g., after members have been cleared by
+// programmatic edits - and returns the number of members removed. Lists are compacted
+// depth first, so a member whose lists all become empty is removed, too. If all the
+// members of a list are removed, the key of the list is removed; if the optional argument
+// 'collapse' is 'true', a list with one remaining member is replaced by the member - as
+// a single element is decoded from XML; the members of a JSON list of lists are not
+// replaced. The Map is modified in place.
+func (mv Map) CompactArrays(collapse ...bool) int {
+	var c bool
+	if len(collapse) == 1 {
+		c = collapse[0]
+	}
+	return compactMap(map[string]interface{}(mv), c)
+}
+
+func compactMap(m map[string]interface{}, collapse bool) int {
+	var n int
+	for k, v := range m {
+		switch v.(type) {
+		case map[string]interface{}:
+			n += compactMap(v.(map[string]interface{}), collapse)
+		case []interface{}:
+			a, nn := compactList(v.([]interface{}), collapse)
+			n += nn
+			switch {
+			case nn == 0:
+			case len(a) == 0:
+				delete(m, k)
+			case len(a) == 1 && collapse:
+				m[k] = a[0]
+			default:
+				m[k] = a
+			}
+		}
+	}
+	return n
+}
+
+// compactList returns the members of 'a' that are not empty and the number removed.
+func compactList(a []interface{}, collapse bool) ([]interface{}, int) {
+	var n, i int
+	for _, v := range a {
+		switch v.(type) {
+		case map[string]interface{}:
+			n += compactMap(v.(map[string]interface{}), collapse)
+		case []interface{}: // a JSON list of lists
+			var nn int
+			v, nn = compactList(v.([]interface{}), collapse)
+			n += nn
+		}
+		if isEmptyMember(v) {
+			n++
+			continue
+		}
+		a[i] = v
+		i++
+	}
+	return a[:i], n
+}
+
+// isEmptyMember reports whether the list member 'v' is removed by mv.CompactArrays().
+func isEmptyMember(v interface{}) bool {
+	switch v.(type) {
+	case nil:
+		return true
+	case string:
+		return v.(string) == ""
+	case map[string]interface{}:
+		return len(v.(map[string]interface{})) == 0
+	}
+	return false
+}
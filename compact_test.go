@@ -0,0 +1,48 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompactArrays(t *testing.T) {
+	fmt.Println("\n------------ compact_test.go")
+	data := func() Map {
+		return Map{"doc": map[string]interface{}{
+			"item": []interface{}{nil, "a", "", map[string]interface{}{}, "b"},
+			"one":  []interface{}{nil, map[string]interface{}{"x": []interface{}{nil}}, "c"},
+			"none": []interface{}{nil, ""},
+			"keep": []interface{}{},
+			"list": []interface{}{[]interface{}{nil, float64(1)}, float64(2)},
+			"text": "",
+		}}
+	}
+
+	m := data()
+	if n := m.CompactArrays(); n != 9 {
+		t.Fatal("n:", n)
+	}
+	want := Map{"doc": map[string]interface{}{
+		"item": []interface{}{"a", "b"},
+		"one":  []interface{}{"c"},
+		"keep": []interface{}{},
+		"list": []interface{}{[]interface{}{float64(1)}, float64(2)},
+		"text": "",
+	}}
+	if !Equal(m, want) {
+		t.Fatal("got:", m, "want:", want)
+	}
+
+	m = data()
+	if n := m.CompactArrays(true); n != 9 {
+		t.Fatal("n:", n)
+	}
+	want["doc"].(map[string]interface{})["one"] = "c"
+	if !Equal(m, want) {
+		t.Fatal("got:", m, "want:", want)
+	}
+
+	if n := m.CompactArrays(true); n != 0 {
+		t.Fatal("n:", n)
+	}
+}
@@ -0,0 +1,115 @@
+// dedupe.go - remove duplicate list members from a Map.
+
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// DedupeArrays removes the duplicate members of the list, []interface{}, value for 'path',
+// keeping the first occurrence of each, and returns the number of members removed.
+// Members are compared by deep equality, ignoring the type of numeric values; so values
+// that were decoded as int, float64, json.Number, etc. are duplicates if they are equal.
+// If all but one member are removed the value is still a list.
+//	NOTE: 'path' must be a dot-separated list of keys; indexed references are not supported.
+func (mv Map) DedupeArrays(path string) (int, error) {
+	pm, err := prevValueByPath(map[string]interface{}(mv), path)
+	if err != nil {
+		return 0, err
+	}
+	key := lastKey(path)
+	list, ok := pm[key].([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("DedupeArrays: value for path is not a list: %s", path)
+	}
+
+	n := len(list)
+	uniq := make([]interface{}, 0, n)
+	for _, v := range list {
+		var dup bool
+		for _, u := range uniq {
+			if valuesEqual(u, v) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			uniq = append(uniq, v)
+		}
+	}
+	pm[key] = uniq
+	return n - len(uniq), nil
+}
+
+// valuesEqual is a deep equality check of Map values that ignores the representation
+// of numeric values, so a value that is cast to float64 on one decode and is an int
+// from NewMapStruct() or a json.Number from a JSON decode compare as equal.
+func valuesEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			vv, ok := bv[k]
+			if !ok || !valuesEqual(v, vv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !valuesEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if af, ok := numberValue(a); ok {
+		bf, ok := numberValue(b)
+		return ok && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numberValue returns the float64 value of numeric types.
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := strconv.ParseFloat(string(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,49 @@
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestDedupeArrays(t *testing.T) {
+	fmt.Println("\n------------ dedupe_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<feed><item id="1"><title>a</title></item><item id="2"><title>b</title></item><item id="1"><title>a</title></item><item id="1"><title>a</title></item></feed>`)
+	m, err := NewMapXml(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := m.DedupeArrays("feed.item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatal("removed:", n)
+	}
+	items, _ := m.ValuesForPath("feed.item")
+	if len(items) != 2 {
+		t.Fatal("items:", items)
+	}
+	if v, _ := m.ValueForPath("feed.item[1].-id"); v != float64(2) {
+		t.Fatal("feed.item[1].-id:", v)
+	}
+
+	// numeric values of different types are duplicates
+	m = Map{"list": []interface{}{1, float64(1), json.Number("1.0"), "1"}}
+	if n, err = m.DedupeArrays("list"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(m["list"].([]interface{})) != 2 {
+		t.Fatal("removed:", n, "list:", m["list"])
+	}
+
+	if _, err = m.DedupeArrays("list.x"); err == nil {
+		t.Fatal("no error for missing path")
+	}
+	m = Map{"a": "b"}
+	if _, err = m.DedupeArrays("a"); err == nil {
+		t.Fatal("no error for non-list value")
+	}
+}
@@ -0,0 +1,104 @@
+// delta.go - the changed subtrees between two Map values.
+
+package mxj
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DeltaDeletedAttr is the attribute label used by DeltaXml to list the keys
+// that were removed from an element. The value is a space-separated, sorted
+// list of the removed Map keys - attribute keys keep their attribute prefix.
+var DeltaDeletedAttr = "delta-deleted"
+
+// DeltaXml encodes the differences between 'old' and 'new' as an XML document.
+// Only added and modified values are encoded; unchanged subtrees are dropped.
+//	- Keys that are in 'new' but not in 'old' are encoded with their 'new' value.
+//	- Keys whose values differ are encoded with their 'new' value. If both values
+//	  are map[string]interface{}, only the changed subelements are encoded.
+//	- List values, []interface{}, are compared as a whole; if they differ the
+//	  entire 'new' list is encoded.
+//	- Keys that are in 'old' but not in 'new' are listed in a DeltaDeletedAttr
+//	  attribute on the parent element - e.g., <doc delta-deleted="author -seq">.
+// If there are no differences, the encoded document is an empty element.
+// The optional 'rootTag' is handled as with mv.Xml().
+//	NOTE: if the attribute prefix is "" - PrependAttrWithHyphen(false) - then the
+//	      DeltaDeletedAttr value is encoded as a subelement.
+func DeltaXml(old, new Map, rootTag ...string) ([]byte, error) {
+	d := deltaMap(map[string]interface{}(old), map[string]interface{}(new))
+	return Map(d).Xml(rootTag...)
+}
+
+// deltaMap returns the changed key:value pairs of 'new' with respect to 'old'.
+func deltaMap(old, new map[string]interface{}) map[string]interface{} {
+	d := make(map[string]interface{})
+
+	var deleted []string
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			deleted = append(deleted, k)
+		}
+	}
+
+	for k, nv := range new {
+		ov, ok := old[k]
+		if !ok {
+			d[k] = nv
+			continue
+		}
+		om, ook := ov.(map[string]interface{})
+		nm, nok := nv.(map[string]interface{})
+		if ook && nok {
+			if dm := deltaMap(om, nm); len(dm) > 0 {
+				d[k] = dm
+			}
+			continue
+		}
+		if !reflect.DeepEqual(ov, nv) {
+			d[k] = nv
+		}
+	}
+
+	if len(deleted) > 0 {
+		sort.Strings(deleted)
+		d[attrPrefix+DeltaDeletedAttr] = strings.Join(deleted, " ")
+	}
+	return d
+}
+
+// Subtract returns a new Map with the content of mv that is not in 'base': keys that
+// are not in 'base' and keys whose values differ, with the mv values. If both values
+// are map[string]interface{}, only the differing content is kept; list values,
+// []interface{}, are compared as a whole. Numeric values are compared without regard
+// to their type - see EqualExcept. The returned values are copies, so neither Map is
+// modified by changes to the result. Unlike DeltaXml, keys that are only in 'base' are
+// not reported. If mv has no content that is not in 'base', an empty Map is returned.
+// The error return is always 'nil'; it's reserved for future options.
+func (mv Map) Subtract(base Map) (Map, error) {
+	return Map(subtractMap(map[string]interface{}(mv), map[string]interface{}(base))), nil
+}
+
+func subtractMap(m, base map[string]interface{}) map[string]interface{} {
+	d := make(map[string]interface{})
+	for k, v := range m {
+		bv, ok := base[k]
+		if !ok {
+			d[k] = copyValue(v)
+			continue
+		}
+		vm, vok := v.(map[string]interface{})
+		bm, bok := bv.(map[string]interface{})
+		if vok && bok {
+			if dm := subtractMap(vm, bm); len(dm) > 0 {
+				d[k] = dm
+			}
+			continue
+		}
+		if !valuesEqual(v, bv) {
+			d[k] = copyValue(v)
+		}
+	}
+	return d
+}
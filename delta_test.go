@@ -0,0 +1,90 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeltaXml(t *testing.T) {
+	fmt.Println("------------ delta_test.go")
+	PrependAttrWithHyphen(true)
+
+	oldDoc := []byte(`<doc><author>William Gaddis</author><title>The Recognitions</title><info seq="1"><pages>976</pages><year>1955</year></info><ref>x</ref></doc>`)
+	newDoc := []byte(`<doc><author>William T. Gaddis</author><title>The Recognitions</title><info seq="1"><pages>976</pages><year>1955</year><isbn>1564781259</isbn></info></doc>`)
+
+	om, err := NewMapXml(oldDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nm, err := NewMapXml(newDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := DeltaXml(om, nm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc delta-deleted="ref"><author>William T. Gaddis</author><info><isbn>1564781259</isbn></info></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// no changes
+	x, err = DeltaXml(om, om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != `<doc/>` {
+		t.Fatal("got:", string(x), "want: <doc/>")
+	}
+
+	// deleted attribute and modified list
+	oldDoc = []byte(`<doc id="1"><item>a</item><item>b</item></doc>`)
+	newDoc = []byte(`<doc><item>a</item><item>c</item></doc>`)
+	om, _ = NewMapXml(oldDoc)
+	nm, _ = NewMapXml(newDoc)
+	x, err = DeltaXml(om, nm, "delta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `<delta><doc delta-deleted="-id"><item>a</item><item>c</item></doc></delta>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	base := Map{"doc": map[string]interface{}{
+		"title": "a",
+		"info":  map[string]interface{}{"pages": float64(976), "year": "1955"},
+		"tags":  []interface{}{"x", "y"},
+		"old":   "gone",
+	}}
+	m := Map{"doc": map[string]interface{}{
+		"title": "a",
+		"info":  map[string]interface{}{"pages": 976, "year": "1956", "isbn": "1"},
+		"tags":  []interface{}{"x", "z"},
+	}}
+	d, err := m.Subtract(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Map{"doc": map[string]interface{}{
+		"info": map[string]interface{}{"year": "1956", "isbn": "1"},
+		"tags": []interface{}{"x", "z"},
+	}}
+	if !EqualExcept(d, want) {
+		t.Fatal("got:", d, "want:", want)
+	}
+
+	// the result is a copy
+	d["doc"].(map[string]interface{})["tags"].([]interface{})[0] = "changed"
+	if v, _ := m.ValueForPath("doc.tags[0]"); v != "x" {
+		t.Fatal("Map modified:", m)
+	}
+
+	if d, _ = m.Subtract(m); len(d) != 0 {
+		t.Fatal("got:", d)
+	}
+}
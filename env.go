@@ -0,0 +1,97 @@
+// env.go - flatten a Map into environment variable name:value pairs.
+
+package mxj
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ToEnv flattens the leaf values of the Map into environment variable name:value pairs;
+// e.g., the value for "order.total" with 'prefix' "app" is "APP_ORDER_TOTAL".
+//	- The path keys are upper case and joined with an underscore, '_'; list members are
+//	  identified by their index - "ITEMS_0_NAME".
+//	- Characters that are not letters, digits or underscores are replaced with '_'.
+//	- The attribute prefix is stripped from attribute keys, and the "#text" key of a simple
+//	  element is dropped; so <total currency="USD">12.5</total> gives TOTAL_CURRENCY and TOTAL.
+//	- Values are formatted per "%v"; 'nil' values are "".
+//	- If 'prefix' is "", names that would begin with a digit are prefixed with '_'.
+// Error is returned if two leaf values have the same name.
+func (mv Map) ToEnv(prefix string) (map[string]string, error) {
+	env := make(map[string]string)
+	paths := make(map[string]string) // for reporting name collisions
+	if err := toEnv(envName(prefix), "", map[string]interface{}(mv), env, paths); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func toEnv(name, path string, v interface{}, env, paths map[string]string) error {
+	switch v.(type) {
+	case map[string]interface{}:
+		for k, vv := range v.(map[string]interface{}) {
+			n := k
+			switch {
+			case k == "#text":
+				n = ""
+			case len(attrPrefix) > 0 && strings.HasPrefix(k, attrPrefix):
+				n = k[len(attrPrefix):]
+			}
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if err := toEnv(joinEnvName(name, envName(n)), p, vv, env, paths); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		for i, vv := range v.([]interface{}) {
+			n := strconv.Itoa(i)
+			if err := toEnv(joinEnvName(name, n), path+"["+n+"]", vv, env, paths); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if name == "" {
+		return fmt.Errorf("ToEnv: no name for value: %v", v)
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	if p, ok := paths[name]; ok {
+		return fmt.Errorf("ToEnv: %s and %s have the same name: %s", p, path, name)
+	}
+	paths[name] = path
+	if v == nil {
+		env[name] = ""
+	} else {
+		env[name] = fmt.Sprintf("%v", v)
+	}
+	return nil
+}
+
+func joinEnvName(name, n string) string {
+	switch {
+	case n == "":
+		return name
+	case name == "":
+		return n
+	}
+	return name + "_" + n
+}
+
+// envName returns upper case 's' with invalid name characters replaced by '_'.
+func envName(s string) string {
+	b := []byte(strings.ToUpper(s))
+	for i, c := range b {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
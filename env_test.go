@@ -0,0 +1,42 @@
+package mxj
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestToEnv(t *testing.T) {
+	fmt.Println("\n------------ env_test.go")
+	m, err := NewMapXml([]byte(`<order id="7"><total currency="USD">12.5</total><items><item>a</item><item>b</item></items><ship-to></ship-to></order>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := m.ToEnv("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"APP_ORDER_ID":             "7",
+		"APP_ORDER_TOTAL":          "12.5",
+		"APP_ORDER_TOTAL_CURRENCY": "USD",
+		"APP_ORDER_ITEMS_ITEM_0":   "a",
+		"APP_ORDER_ITEMS_ITEM_1":   "b",
+		"APP_ORDER_SHIP_TO":        "",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Fatal("got:", env, "want:", want)
+	}
+
+	env, err = Map{"1st": nil}.ToEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := env["_1ST"]; !ok || v != "" {
+		t.Fatal("got:", env)
+	}
+
+	if _, err = (Map{"a-b": "1", "a_b": "2"}).ToEnv(""); err == nil {
+		t.Fatal("no error for name collision")
+	}
+}
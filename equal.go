@@ -0,0 +1,192 @@
+// equal.go - compare Map values and XML documents.
+
+package mxj
+
+import "strings"
+
+// EqualExcept reports whether 'a' and 'b' are deeply equal after the values for
+// 'ignorePaths' are removed from both - e.g., volatile timestamp and ID values.
+// Numeric values are compared without regard to their type: 1, float64(1) and
+// json.Number("1") are equal. Neither Map is modified.
+// The 'ignorePaths' are dot-separated lists of keys; a "*" key matches any key and
+// all members of lists are handled, but indexed list references are not supported.
+func EqualExcept(a, b Map, ignorePaths ...string) bool {
+	av, bv := interface{}(map[string]interface{}(a)), interface{}(map[string]interface{}(b))
+	if len(ignorePaths) > 0 {
+		av, bv = copyValue(av), copyValue(bv)
+		for _, p := range ignorePaths {
+			keys := strings.Split(p, ".")
+			removePathKeys(av, keys)
+			removePathKeys(bv, keys)
+		}
+	}
+	return valuesEqual(av, bv)
+}
+
+// removePathKeys deletes the values for the path 'keys' beneath 'v'.
+func removePathKeys(v interface{}, keys []string) {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		for k, vv := range m {
+			if keys[0] != "*" && keys[0] != k {
+				continue
+			}
+			if len(keys) == 1 {
+				delete(m, k)
+				continue
+			}
+			removePathKeys(vv, keys[1:])
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			removePathKeys(vv, keys)
+		}
+	}
+}
+
+// EqualOption is an option for Equal and XmlEqual.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	cast            bool
+	ignoreListOrder bool
+	emptyIsAbsent   bool
+	ignorePaths     []string
+}
+
+// EqualCastValues decodes the documents with 'cast' set to 'true' - see NewMapXml - so
+// that numeric and boolean text is compared by value; e.g., "1.50" and "1.5" are equal.
+// (Only applicable to XmlEqual.)
+func EqualCastValues() EqualOption {
+	return func(o *equalOptions) { o.cast = true }
+}
+
+// EqualIgnoreListOrder compares list values - repeated sibling elements with the same
+// tag - without regard to the order of their members.
+func EqualIgnoreListOrder() EqualOption {
+	return func(o *equalOptions) { o.ignoreListOrder = true }
+}
+
+// EqualIgnorePaths ignores the values for 'paths' - see EqualExcept.
+func EqualIgnorePaths(paths ...string) EqualOption {
+	return func(o *equalOptions) { o.ignorePaths = append(o.ignorePaths, paths...) }
+}
+
+// EqualTreatEmptyAsAbsent treats a key with an empty string value - e.g., an empty
+// element, <middleName/> - as equal to the key's absence; so {"a":"1", "b":""} and
+// {"a":"1"} are equal.
+func EqualTreatEmptyAsAbsent() EqualOption {
+	return func(o *equalOptions) { o.emptyIsAbsent = true }
+}
+
+// Equal reports whether 'a' and 'b' are deeply equal per the 'opts'. As with
+// EqualExcept, numeric values are compared without regard to their type. Neither
+// Map is modified.
+func Equal(a, b Map, opts ...EqualOption) bool {
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.equal(a, b)
+}
+
+func (o *equalOptions) equal(a, b Map) bool {
+	av, bv := interface{}(map[string]interface{}(a)), interface{}(map[string]interface{}(b))
+	if len(o.ignorePaths) > 0 || o.emptyIsAbsent {
+		av, bv = copyValue(av), copyValue(bv)
+	}
+	for _, p := range o.ignorePaths {
+		keys := strings.Split(p, ".")
+		removePathKeys(av, keys)
+		removePathKeys(bv, keys)
+	}
+	if o.emptyIsAbsent {
+		removeEmptyStrings(av)
+		removeEmptyStrings(bv)
+	}
+	if o.ignoreListOrder {
+		return valuesEqualUnordered(av, bv)
+	}
+	return valuesEqual(av, bv)
+}
+
+// removeEmptyStrings deletes the keys with "" values beneath 'v'.
+func removeEmptyStrings(v interface{}) {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		for k, vv := range m {
+			if s, ok := vv.(string); ok && s == "" {
+				delete(m, k)
+				continue
+			}
+			removeEmptyStrings(vv)
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			removeEmptyStrings(vv)
+		}
+	}
+}
+
+// XmlEqual decodes the XML documents 'a' and 'b' with NewMapXml() and reports whether
+// they are semantically equal. Formatting - white space between elements, the order of
+// attributes, the order of sibling elements with different tags, the quote character,
+// empty element syntax, comments, etc. - is not significant. The order of repeated
+// sibling elements with the same tag is significant unless EqualIgnoreListOrder()
+// is passed. Error is returned if either document cannot be decoded.
+func XmlEqual(a, b []byte, opts ...EqualOption) (bool, error) {
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	am, err := NewMapXml(a, o.cast)
+	if err != nil {
+		return false, err
+	}
+	bm, err := NewMapXml(b, o.cast)
+	if err != nil {
+		return false, err
+	}
+	return o.equal(am, bm), nil
+}
+
+// valuesEqualUnordered is valuesEqual, but lists are equal if their members can be
+// paired off regardless of order.
+func valuesEqualUnordered(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			vv, ok := bv[k]
+			if !ok || !valuesEqualUnordered(v, vv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		used := make([]bool, len(bv))
+		for _, v := range av {
+			var found bool
+			for j, vv := range bv {
+				if !used[j] && valuesEqualUnordered(v, vv) {
+					used[j], found = true, true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+	return valuesEqual(a, b)
+}
@@ -0,0 +1,95 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEqualExcept(t *testing.T) {
+	fmt.Println("\n------------ equal_test.go")
+	PrependAttrWithHyphen(true)
+
+	a, _ := NewMapXml([]byte(`<doc id="1"><ts>2020-01-01</ts><item><rid>a</rid><v>1</v></item><item><rid>b</rid><v>2</v></item></doc>`), true)
+	b, _ := NewMapXml([]byte(`<doc id="2"><ts>2021-06-30</ts><item><rid>c</rid><v>1</v></item><item><rid>d</rid><v>2</v></item></doc>`), true)
+
+	if EqualExcept(a, b) {
+		t.Fatal("equal without ignored paths")
+	}
+	if EqualExcept(a, b, "doc.ts", "doc.-id") {
+		t.Fatal("equal without doc.item.rid ignored")
+	}
+	if !EqualExcept(a, b, "doc.ts", "doc.-id", "doc.item.rid") {
+		t.Fatal("not equal with ignored paths")
+	}
+	if !EqualExcept(a, b, "*.ts", "doc.-id", "doc.*.rid") {
+		t.Fatal("not equal with wildcard ignored paths")
+	}
+	if v, _ := a.ValueForPath("doc.ts"); v != "2020-01-01" {
+		t.Fatal("Map modified:", a)
+	}
+
+	// numeric types
+	if !EqualExcept(Map{"a": 1, "b": "x"}, Map{"a": float64(1), "b": "y"}, "b") {
+		t.Fatal("numeric values not equal")
+	}
+}
+
+func TestXmlEqual(t *testing.T) {
+	a := []byte(`<doc id="1" type="x"><a>1.50</a><b/><item>p</item><item>q</item></doc>`)
+	b := []byte("<doc type='x' id='1'>\n  <!-- reordered -->\n  <item>p</item>\n  <b></b>\n  <a>1.50</a>\n  <item>q</item>\n</doc>")
+
+	eq, err := XmlEqual(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !eq {
+		t.Fatal("not equal")
+	}
+
+	c := []byte(`<doc id="1" type="x"><a>1.5</a><b/><item>q</item><item>p</item></doc>`)
+	if eq, _ = XmlEqual(a, c); eq {
+		t.Fatal("equal without options")
+	}
+	if eq, _ = XmlEqual(a, c, EqualCastValues()); eq {
+		t.Fatal("equal with list order")
+	}
+	if eq, _ = XmlEqual(a, c, EqualCastValues(), EqualIgnoreListOrder()); !eq {
+		t.Fatal("not equal with options")
+	}
+	if eq, _ = XmlEqual(a, c, EqualIgnoreListOrder(), EqualIgnorePaths("doc.a")); !eq {
+		t.Fatal("not equal with ignored path")
+	}
+
+	if _, err = XmlEqual(a, []byte(`<doc>`)); err == nil {
+		t.Fatal("no error for bad XML")
+	}
+}
+
+func TestEqualTreatEmptyAsAbsent(t *testing.T) {
+	a := Map{"person": map[string]interface{}{"first": "Ann", "middleName": "", "last": "Lee"}}
+	b := Map{"person": map[string]interface{}{"first": "Ann", "last": "Lee"}}
+	if Equal(a, b) {
+		t.Fatal("equal without option")
+	}
+	if !Equal(a, b, EqualTreatEmptyAsAbsent()) {
+		t.Fatal("not equal with option")
+	}
+	if !Equal(b, a, EqualTreatEmptyAsAbsent()) {
+		t.Fatal("not equal with option, reversed")
+	}
+	if _, ok := a["person"].(map[string]interface{})["middleName"]; !ok {
+		t.Fatal("Map modified:", a)
+	}
+	b["person"].(map[string]interface{})["middleName"] = "J"
+	if Equal(a, b, EqualTreatEmptyAsAbsent()) {
+		t.Fatal("equal with non-empty value")
+	}
+
+	eq, err := XmlEqual([]byte(`<p><first>Ann</first><middleName/></p>`), []byte(`<p><first>Ann</first></p>`), EqualTreatEmptyAsAbsent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !eq {
+		t.Fatal("XmlEqual: not equal with option")
+	}
+}
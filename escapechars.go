@@ -6,6 +6,7 @@ package mxj
 
 import (
 	"bytes"
+	"strings"
 )
 
 var xmlEscapeChars bool
@@ -67,6 +68,69 @@ func escapeChars(s string) string {
 	return string(b)
 }
 
+// XmlEscapeMode is the set of characters escaped per XMLEscapeChars(true) - see
+// SetXmlEscapeMode.
+type XmlEscapeMode int
+
+const (
+	EscapeFull    XmlEscapeMode = iota // '"', '\'', '<', '>' and '&' in all values; the default
+	EscapeMinimal                      // '<', '&' and the '>' of "]]>" in element text, and '"' in attribute values
+	EscapeContext                      // the characters that are significant in element text or attribute values
+)
+
+// escapeCanonical is the escaping of c14n - see mv.XmlCanonical().
+const escapeCanonical XmlEscapeMode = -1
+
+var xmlEscapeMode XmlEscapeMode
+
+// SetXmlEscapeMode sets the characters that are escaped by mv.Xml(), mv.XmlIndent(),
+// etc., when escaping is enabled with XMLEscapeChars(true). EscapeMinimal escapes only
+// what is required for the XML to be well-formed - attribute values are quoted with '"',
+// and '>' is only escaped in element text that has "]]>".
+// EscapeContext escapes the characters that are significant in each context; in
+// attribute values tab, newline and carriage return are written as character
+// references, "&#9;", "&#10;" and "&#13;", so that they are not normalized to spaces
+// by conforming XML parsers. (Not applicable to MapSeq values.)
+func SetXmlEscapeMode(mode XmlEscapeMode) {
+	xmlEscapeMode = mode
+}
+
+var (
+	escapeMinimalText = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `]]>`, `]]&gt;`)
+	escapeMinimalAttr = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `"`, `&quot;`)
+	escapeContextText = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`)
+	escapeContextAttr = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `"`, `&quot;`,
+		"\t", `&#9;`, "\n", `&#10;`, "\r", `&#13;`)
+	escapeCanonicalText = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`, "\r", `&#xD;`)
+	escapeCanonicalAttr = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `"`, `&quot;`,
+		"\t", `&#x9;`, "\n", `&#xA;`, "\r", `&#xD;`)
+)
+
+// escapeText and escapeAttr escape element text and attribute values per 'mode'.
+func escapeText(s string, mode XmlEscapeMode) string {
+	switch mode {
+	case EscapeMinimal:
+		return escapeMinimalText.Replace(s)
+	case EscapeContext:
+		return escapeContextText.Replace(s)
+	case escapeCanonical:
+		return escapeCanonicalText.Replace(s)
+	}
+	return escapeChars(s)
+}
+
+func escapeAttr(s string, mode XmlEscapeMode) string {
+	switch mode {
+	case EscapeMinimal:
+		return escapeMinimalAttr.Replace(s)
+	case EscapeContext:
+		return escapeContextAttr.Replace(s)
+	case escapeCanonical:
+		return escapeCanonicalAttr.Replace(s)
+	}
+	return escapeChars(s)
+}
+
 // per issue #84, escape CharData values from xml.Decoder
 
 var xmlEscapeCharsDecoder bool
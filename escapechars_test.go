@@ -111,3 +111,46 @@ func TestXMLSeqEscapeChars2(t *testing.T) {
 	}
 	fmt.Println("m:", string(x))
 }
+
+func TestSetXmlEscapeMode(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	XMLEscapeChars(true)
+	defer XMLEscapeChars(false)
+	defer SetXmlEscapeMode(EscapeFull)
+
+	m := Map{"doc": map[string]interface{}{"-a": "x\"'<>&\ty", "t": `"'<>&`, "u": "a]]>b>"}}
+	checks := []struct {
+		mode XmlEscapeMode
+		want string
+	}{
+		{EscapeFull, `<doc a="x&quot;&apos;&lt;&gt;&amp;` + "\t" + `y"><t>&quot;&apos;&lt;&gt;&amp;</t><u>a]]&gt;b&gt;</u></doc>`},
+		{EscapeMinimal, `<doc a="x&quot;'&lt;>&amp;` + "\t" + `y"><t>"'&lt;>&amp;</t><u>a]]&gt;b></u></doc>`},
+		{EscapeContext, `<doc a="x&quot;'&lt;>&amp;&#9;y"><t>"'&lt;&gt;&amp;</t><u>a]]&gt;b&gt;</u></doc>`},
+	}
+	for _, c := range checks {
+		SetXmlEscapeMode(c.mode)
+		x, err := m.Xml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(x) != c.want {
+			t.Fatal(c.mode, "got:", string(x), "want:", c.want)
+		}
+		// the per-call setting
+		SetXmlEscapeMode(EscapeFull)
+		if x, err = m.Marshal(EncodeOptions{EscapeChars: true, EscapeMode: c.mode}); err != nil {
+			t.Fatal(err)
+		}
+		if string(x) != c.want {
+			t.Fatal(c.mode, "Marshal got:", string(x), "want:", c.want)
+		}
+		// the values decode as they were encoded
+		mm, err := NewMapXml(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equal(mm, m) {
+			t.Fatal(c.mode, "decoded:", mm, "want:", m)
+		}
+	}
+}
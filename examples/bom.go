@@ -28,7 +28,7 @@ Content-Type: text/plain
 		`)
 
 	// don't prepend attributes with '-'
-	mxj.SetAttrPrefix("")
+	mxj.PrependAttrWithHyphen(false)
 
 	// parse the data as a map[string]interface{} value
 	m, err := mxj.NewMapXml(data)
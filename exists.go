@@ -7,3 +7,16 @@ func (mv Map) Exists(path string, subkeys ...string) (bool, error) {
 	v, err := mv.ValuesForPath(path, subkeys...)
 	return (err == nil && len(v) > 0), err
 }
+
+// KeyExists checks whether the path exists and, if it does, whether its value is 'nil' -
+// e.g., a JSON null or an element decoded with XsiNil. (An empty XML element decodes as
+// "", so it exists and is not 'nil'.) If 'path' matches more than one value, as with
+// wildcards, 'isNil' is for the first value - see ValueForPath. If err != nil then 'false'
+// is returned along with the error encountered parsing the "path" argument.
+func (mv Map) KeyExists(path string) (exists bool, isNil bool, err error) {
+	v, err := mv.ValuesForPath(path)
+	if err != nil || len(v) == 0 {
+		return false, false, err
+	}
+	return true, v[0] == nil, nil
+}
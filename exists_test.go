@@ -23,6 +23,37 @@ func TestExists(t *testing.T) {
 	}
 }
 
+func TestKeyExists(t *testing.T) {
+	m, err := NewMapJson([]byte(`{"doc":{"name":"a","empty":"","none":null,"list":[null,1]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		path          string
+		exists, isNil bool
+	}{
+		{"doc.name", true, false},
+		{"doc.empty", true, false},
+		{"doc.none", true, true},
+		{"doc.list[0]", true, true},
+		{"doc.list[1]", true, false},
+		{"doc.missing", false, false},
+		{"doc.none.x", false, false},
+	}
+	for _, c := range checks {
+		exists, isNil, err := m.KeyExists(c.path)
+		if err != nil {
+			t.Fatal(c.path, err)
+		}
+		if exists != c.exists || isNil != c.isNil {
+			t.Fatal(c.path, "got:", exists, isNil, "want:", c.exists, c.isNil)
+		}
+	}
+	if _, _, err = m.KeyExists("doc.list[x]"); err == nil {
+		t.Fatal("no error for bad index")
+	}
+}
+
 /*
 var existsDoc = []byte(`
 <doc>
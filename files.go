@@ -1,6 +1,7 @@
 package mxj
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -210,6 +211,129 @@ func (mvs Maps) XmlStringIndent(prefix, indent string) (string, error) {
 	return s, nil
 }
 
+// MapsToXml encodes 'maps' as a single XML document with 'rootTag' as the root element
+// and each Map as a repeated subelement - the counterpart of decoding a stream of XML docs
+// with NewMapXmlReader(), etc. If 'elementTag' is "", each Map is encoded as with mv.Xml() -
+// a Map with a single key, e.g., as decoded from <msg>...</msg>, keeps it as its tag; otherwise
+// each Map is the value of an 'elementTag' element, as with mv.Xml(elementTag).
+// If 'rootTag' is "", DefaultRootTag is used.
+func MapsToXml(maps []Map, rootTag, elementTag string) ([]byte, error) {
+	if rootTag == "" {
+		rootTag = DefaultRootTag
+	}
+	if len(maps) == 0 {
+		return mapsXmlEnd([]byte("<" + rootTag + "/>")), nil
+	}
+	b := new(bytes.Buffer)
+	b.WriteString("<" + rootTag + ">")
+	for _, v := range maps {
+		var x []byte
+		var err error
+		if elementTag == "" {
+			x, err = v.Xml()
+		} else {
+			x, err = v.Xml(elementTag)
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.Write(bytes.TrimSuffix(x, []byte("\n"))) // per XmlTrailingNewline
+	}
+	b.WriteString("</" + rootTag + ">")
+	return mapsXmlEnd(b.Bytes()), nil
+}
+
+// MapsToXmlIndent is MapsToXml() encoded as pretty XML, as with mv.XmlIndent().
+func MapsToXmlIndent(maps []Map, rootTag, elementTag, prefix, indent string) ([]byte, error) {
+	if rootTag == "" {
+		rootTag = DefaultRootTag
+	}
+	if len(maps) == 0 {
+		return mapsXmlEnd([]byte(prefix + "<" + rootTag + "/>")), nil
+	}
+	b := new(bytes.Buffer)
+	b.WriteString(prefix + "<" + rootTag + ">\n")
+	for _, v := range maps {
+		var x []byte
+		var err error
+		if elementTag == "" {
+			x, err = v.XmlIndent(prefix+indent, indent)
+		} else {
+			x, err = v.XmlIndent(prefix+indent, indent, elementTag)
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.Write(bytes.TrimSuffix(x, []byte("\n"))) // per XmlTrailingNewline
+		b.WriteString("\n")
+	}
+	b.WriteString(prefix + "</" + rootTag + ">")
+	return mapsXmlEnd(b.Bytes()), nil
+}
+
+// mapsXmlEnd appends a newline to the document per XmlTrailingNewline.
+func mapsXmlEnd(x []byte) []byte {
+	if xmlTrailingNewline {
+		return append(x, '\n')
+	}
+	return x
+}
+
+// MapsJsonWriter writes a sequence of Maps on an io.Writer as a single JSON array.
+// See MapsToJsonWriter().
+type MapsJsonWriter struct {
+	w      io.Writer
+	safe   []bool
+	n      int
+	closed bool
+}
+
+// MapsToJsonWriter returns a MapsJsonWriter that writes the Maps passed to its Add()
+// method as the members of a JSON array - "[", the comma-separated mv.Json() values,
+// and "]" when Close() is called - so a stream of Maps can be written as one JSON
+// array without holding them all in memory. The optional 'safeEncoding' argument is
+// as for mv.Json(). Close() does not close 'w'.
+func MapsToJsonWriter(w io.Writer, safeEncoding ...bool) *MapsJsonWriter {
+	return &MapsJsonWriter{w: w, safe: safeEncoding}
+}
+
+// Add writes 'm' as the next member of the JSON array.
+func (mw *MapsJsonWriter) Add(m Map) error {
+	if mw.closed {
+		return fmt.Errorf("MapsJsonWriter: Add after Close")
+	}
+	j, err := m.Json(mw.safe...)
+	if err != nil {
+		return err
+	}
+	sep := []byte(",")
+	if mw.n == 0 {
+		sep = []byte("[")
+	}
+	if _, err = mw.w.Write(sep); err != nil {
+		return err
+	}
+	if _, err = mw.w.Write(j); err != nil {
+		return err
+	}
+	mw.n++
+	return nil
+}
+
+// Close terminates the JSON array; if no Maps were added, "[]" is written.
+func (mw *MapsJsonWriter) Close() error {
+	if mw.closed {
+		return nil
+	}
+	mw.closed = true
+	end := "]"
+	if mw.n == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(mw.w, end)
+	return err
+}
+
 // JsonFile - write Maps to named file as JSON
 // Note: the file will be created, if necessary; if it exists it will be truncated.
 // If you need to append to a file, open it and use JsonWriter method.
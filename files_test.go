@@ -1,6 +1,7 @@
 package mxj
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 )
@@ -166,3 +167,72 @@ func TestXmlFile(t *testing.T) {
 	}
 	fmt.Println("files_test_indent.xml written")
 }
+
+func TestMapsToXml(t *testing.T) {
+	var maps []Map
+	for _, v := range []string{`<msg><id>1</id></msg>`, `<msg><id>2</id></msg>`} {
+		m, err := NewMapXml([]byte(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		maps = append(maps, m)
+	}
+
+	x, err := MapsToXml(maps, "msgs", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<msgs><msg><id>1</id></msg><msg><id>2</id></msg></msgs>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	x, err = MapsToXml(maps, "", "item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><item><msg><id>1</id></msg></item><item><msg><id>2</id></msg></item></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// same as XmlIndent of the equivalent Map
+	m, _ := NewMapXml([]byte(`<msgs><msg><id>1</id></msg><msg><id>2</id></msg></msgs>`))
+	want, _ := m.XmlIndent("", "  ")
+	x, err = MapsToXmlIndent(maps, "msgs", "", "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != string(want) {
+		t.Fatal("got:", string(x), "want:", string(want))
+	}
+	if x, _ = MapsToXml(nil, "msgs", ""); string(x) != `<msgs/>` {
+		t.Fatal("got:", string(x))
+	}
+}
+
+func TestMapsToJsonWriter(t *testing.T) {
+	b := new(bytes.Buffer)
+	mw := MapsToJsonWriter(b)
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != `[]` {
+		t.Fatal("got:", b.String())
+	}
+
+	b.Reset()
+	mw = MapsToJsonWriter(b)
+	for _, v := range []string{`<msg><id>1</id></msg>`, `<msg><id>2</id></msg>`} {
+		m, _ := NewMapXml([]byte(v))
+		if err := mw.Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"msg":{"id":"1"}},{"msg":{"id":"2"}}]`; b.String() != want {
+		t.Fatal("got:", b.String(), "want:", want)
+	}
+	if err := mw.Add(Map{}); err == nil {
+		t.Fatal("no error for Add after Close")
+	}
+}
@@ -0,0 +1,71 @@
+// filter.go - remove the leaf values of a Map that fail a test.
+
+package mxj
+
+import "strconv"
+
+// FilterValues removes the leaf values - see mv.LeafNodes() - for which 'pred' returns
+// 'false' and returns the number of values removed. The 'path' argument is the
+// LeafNode.Path of the value, so attribute values and "#text" values are included.
+// Maps and lists that are emptied by removing values are also removed; those that
+// were already empty are not changed.
+func (mv Map) FilterValues(pred func(path string, v interface{}) bool) int {
+	var n int
+	filterMap(map[string]interface{}(mv), "", pred, &n)
+	return n
+}
+
+// filterValue returns 'false' if 'v' is to be removed.
+func filterValue(path string, v interface{}, pred func(string, interface{}) bool, n *int) (interface{}, bool) {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		if len(m) == 0 {
+			return v, true
+		}
+		return m, filterMap(m, path, pred, n)
+	case []interface{}:
+		list := v.([]interface{})
+		if len(list) == 0 {
+			return v, true
+		}
+		kept := list[:0]
+		for i, lv := range list {
+			var p string
+			if useDotNotation {
+				p = path + "." + strconv.Itoa(i)
+			} else {
+				p = path + "[" + strconv.Itoa(i) + "]"
+			}
+			if lv, ok := filterValue(p, lv, pred, n); ok {
+				kept = append(kept, lv)
+			}
+		}
+		// clear the dropped members for the garbage collector
+		for i := len(kept); i < len(list); i++ {
+			list[i] = nil
+		}
+		return kept, len(kept) > 0
+	}
+	if !pred(path, v) {
+		*n++
+		return v, false
+	}
+	return v, true
+}
+
+// filterMap returns 'false' if 'm' is emptied.
+func filterMap(m map[string]interface{}, path string, pred func(string, interface{}) bool, n *int) bool {
+	for k, v := range m {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if v, ok := filterValue(p, v, pred, n); ok {
+			m[k] = v
+		} else {
+			delete(m, k)
+		}
+	}
+	return len(m) > 0
+}
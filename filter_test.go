@@ -0,0 +1,47 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFilterValues(t *testing.T) {
+	fmt.Println("\n------------ filter_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc id="1"><big>xxxxxxxxxx</big><list><item>a</item><item>xxxxxxxxxx</item></list><only><x>xxxxxxxxxx</x></only><none/></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	n := m.FilterValues(func(path string, v interface{}) bool {
+		if s, ok := v.(string); ok && len(s) > 5 {
+			paths = append(paths, path)
+			return false
+		}
+		return true
+	})
+	if n != 3 {
+		t.Fatal("removed:", n, paths)
+	}
+	x, _ := m.Xml()
+	if want := `<doc id="1"><list><item>a</item></list><none/></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	var found bool
+	for _, p := range paths {
+		found = found || p == "doc.list.item[1]"
+	}
+	if !found {
+		t.Fatal("no list member path:", paths)
+	}
+
+	m = Map{"a": []interface{}{1, 2}, "b": map[string]interface{}{}}
+	if n = m.FilterValues(func(string, interface{}) bool { return false }); n != 2 {
+		t.Fatal("removed:", n)
+	}
+	if fmt.Sprint(m) != "map[b:map[]]" {
+		t.Fatal("m:", m)
+	}
+}
@@ -0,0 +1,61 @@
+// find.go - depth-first search for the first node matching a predicate.
+
+package mxj
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Find returns the path and value of the first node of the Map for which 'pred'
+// returns true, and whether a node was found. The nodes are each key:value pair - including
+// those with a list value, "a.b" - and each member of a list value - the path of a list member
+// is subscripted, e.g., "a.b[1]". Nodes are visited depth first, parents before their
+// subelements and a list before its members, with the keys of a map alphabetized; the
+// search stops at the first match.
+func (mv Map) Find(pred func(path string, v interface{}) bool) (string, interface{}, bool) {
+	return findMap(map[string]interface{}(mv), "", pred)
+}
+
+func findMap(m map[string]interface{}, path string, pred func(string, interface{}) bool) (string, interface{}, bool) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if fp, fv, ok := findValue(m[k], p, pred); ok {
+			return fp, fv, true
+		}
+	}
+	return "", nil, false
+}
+
+func findValue(v interface{}, path string, pred func(string, interface{}) bool) (string, interface{}, bool) {
+	switch v := v.(type) {
+	case []interface{}:
+		if pred(path, v) {
+			return path, v, true
+		}
+		for i, vv := range v {
+			if fp, fv, ok := findValue(vv, path+"["+strconv.Itoa(i)+"]", pred); ok {
+				return fp, fv, true
+			}
+		}
+		return "", nil, false
+	case map[string]interface{}:
+		if pred(path, v) {
+			return path, v, true
+		}
+		return findMap(v, path, pred)
+	}
+	if pred(path, v) {
+		return path, v, true
+	}
+	return "", nil, false
+}
@@ -0,0 +1,61 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	fmt.Println("\n------------ find_test.go")
+	m := Map{"doc": map[string]interface{}{
+		"title": "mxj",
+		"books": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"-seq": "1", "author": "a"},
+				map[string]interface{}{"-seq": "2", "author": "b"},
+			},
+		},
+	}}
+
+	path, v, ok := m.Find(func(p string, v interface{}) bool {
+		s, ok := v.(string)
+		return ok && s == "b"
+	})
+	if !ok || path != "doc.books.book[1].author" || v.(string) != "b" {
+		t.Fatal("got:", path, v, ok)
+	}
+
+	// containers are nodes, visited before their subelements
+	path, v, ok = m.Find(func(p string, v interface{}) bool {
+		vm, ok := v.(map[string]interface{})
+		return ok && vm["-seq"] != nil
+	})
+	if !ok || path != "doc.books.book[0]" || v.(map[string]interface{})["author"] != "a" {
+		t.Fatal("got:", path, v, ok)
+	}
+
+	// keys are alphabetized and the search stops at the first match
+	var visited []string
+	path, _, ok = m.Find(func(p string, v interface{}) bool {
+		visited = append(visited, p)
+		_, ok := v.(string)
+		return ok
+	})
+	want := []string{"doc", "doc.books", "doc.books.book", "doc.books.book[0]", "doc.books.book[0].-seq"}
+	if !ok || path != want[len(want)-1] || fmt.Sprint(visited) != fmt.Sprint(want) {
+		t.Fatal("got:", path, visited)
+	}
+
+	// a list-valued key is a node
+	path, v, ok = m.Find(func(p string, v interface{}) bool {
+		l, ok := v.([]interface{})
+		return ok && len(l) == 2
+	})
+	if !ok || path != "doc.books.book" || len(v.([]interface{})) != 2 {
+		t.Fatal("got:", path, v, ok)
+	}
+
+	if path, v, ok = m.Find(func(string, interface{}) bool { return false }); ok || path != "" || v != nil {
+		t.Fatal("found:", path, v)
+	}
+}
@@ -0,0 +1,84 @@
+// firstmatch.go - resolve the first match for a wildcard path.
+
+package mxj
+
+import (
+	"sort"
+	"strconv"
+)
+
+// FirstMatch returns the concrete path and value of the first match for 'pattern'.
+// The 'pattern' is as for ValuesForPath() - it can contain wildcards, "*", and indexed
+// array references. The concrete path has no wildcards and every list member is
+// identified by its index - e.g., "doc.*.title" may resolve to "doc.books.book[0].title";
+// it can be used with ValueForPath(), etc.
+// Map keys are searched in sorted order and list members in sequence, so the result
+// is deterministic. If there is no match, PathNotExistError is returned.
+func (mv Map) FirstMatch(pattern string) (string, interface{}, error) {
+	keys, err := parsePath(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	if path, v, ok := firstMatch("", map[string]interface{}(mv), keys); ok {
+		return path, v, nil
+	}
+	return "", nil, PathNotExistError
+}
+
+func firstMatch(path string, v interface{}, keys []*key) (string, interface{}, bool) {
+	if len(keys) == 0 {
+		if a, ok := v.([]interface{}); ok {
+			// as with ValuesForPath the list members are the values
+			if len(a) == 0 {
+				return "", nil, false
+			}
+			return path + "[0]", a[0], true
+		}
+		return path, v, true
+	}
+
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		var names []string
+		if keys[0].name == "*" {
+			names = make([]string, 0, len(m))
+			for k := range m {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+		} else if _, ok := m[keys[0].name]; ok {
+			names = []string{keys[0].name}
+		}
+		for _, k := range names {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			vv := m[k]
+			if keys[0].isArray {
+				// indexed list member; a singleton is member [0]
+				if a, ok := vv.([]interface{}); ok {
+					if keys[0].position >= len(a) {
+						continue
+					}
+					p += "[" + strconv.Itoa(keys[0].position) + "]"
+					vv = a[keys[0].position]
+				} else if keys[0].position != 0 {
+					continue
+				}
+			}
+			if rp, rv, ok := firstMatch(p, vv, keys[1:]); ok {
+				return rp, rv, true
+			}
+		}
+	case []interface{}:
+		// key may be buried in list
+		for i, vv := range v.([]interface{}) {
+			if rp, rv, ok := firstMatch(path+"["+strconv.Itoa(i)+"]", vv, keys); ok {
+				return rp, rv, true
+			}
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,36 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFirstMatch(t *testing.T) {
+	fmt.Println("\n------------ firstmatch_test.go")
+	m, err := NewMapXml([]byte(`<doc><books><book><title>A</title></book><book><title>B</title><isbn>1</isbn></book></books><mag><title>M</title></mag></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pattern, want := range map[string][2]string{
+		"doc.*.title":           {"doc.mag.title", "M"},
+		"doc.books.book.title":  {"doc.books.book[0].title", "A"},
+		"doc.*.book.isbn":       {"doc.books.book[1].isbn", "1"},
+		"*.books.book[1].title": {"doc.books.book[1].title", "B"},
+		"doc.books.*.*":         {"doc.books.book[0].title", "A"},
+	} {
+		path, v, err := m.FirstMatch(pattern)
+		if err != nil {
+			t.Fatal(pattern, err)
+		}
+		if path != want[0] || v.(string) != want[1] {
+			t.Fatal(pattern, "got:", path, v, "want:", want)
+		}
+		// the concrete path resolves to the same value
+		if vv, err := m.ValueForPath(path); err != nil || vv != v {
+			t.Fatal(path, "ValueForPath:", vv, err)
+		}
+	}
+	if _, _, err = m.FirstMatch("doc.*.author"); err != PathNotExistError {
+		t.Fatal("doc.*.author, err:", err)
+	}
+}
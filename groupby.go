@@ -0,0 +1,36 @@
+// groupby.go - bucket list members by the value of a subkey.
+
+package mxj
+
+import "fmt"
+
+// GroupBy returns the values for 'path' grouped by the value for the 'byKey' path
+// relative to each value. The group key is the string value - per "%v" formatting -
+// of the first 'byKey' value; values that do not have a 'byKey' value are grouped
+// with the key "". Within a group the values keep the sequence in which they were found.
+//	'path' is as for ValuesForPath() and will normally identify a list of elements.
+//	'byKey' can use dot-notation - e.g., "info.-type" - and indexed arrays.
+// Error is returned if a value for 'path' is not a map[string]interface{} value.
+func (mv Map) GroupBy(path, byKey string) (map[string][]Map, error) {
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]Map)
+	for i, v := range vals {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("GroupBy: value #%d for path %s is not a map: %T", i, path, v)
+		}
+		var k string
+		kv, err := Map(m).ValuesForPath(byKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(kv) > 0 {
+			k = fmt.Sprintf("%v", kv[0])
+		}
+		groups[k] = append(groups[k], Map(m))
+	}
+	return groups, nil
+}
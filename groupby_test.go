@@ -0,0 +1,49 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	fmt.Println("\n------------ groupby_test.go")
+	PrependAttrWithHyphen(true)
+	data := []byte(`<orders>
+		<order id="1"><region>east</region><total>10</total></order>
+		<order id="2"><region>west</region><total>20</total></order>
+		<order id="3"><region>east</region><total>30</total></order>
+		<order id="4"><total>40</total></order>
+	</orders>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := m.GroupBy("orders.order", "region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g) != 3 || len(g["east"]) != 2 || len(g["west"]) != 1 || len(g[""]) != 1 {
+		t.Fatal("groups:", g)
+	}
+	if g["east"][0]["-id"] != "1" || g["east"][1]["-id"] != "3" {
+		t.Fatal("east group sequence:", g["east"])
+	}
+	if g[""][0]["-id"] != "4" {
+		t.Fatal("no region group:", g[""])
+	}
+
+	// cast values and attribute key
+	m, _ = NewMapXml(data, true)
+	g, err = m.GroupBy("orders.order", "-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g) != 4 || len(g["2"]) != 1 {
+		t.Fatal("groups:", g)
+	}
+
+	if _, err = m.GroupBy("orders.order.region", "x"); err == nil {
+		t.Fatal("no error for non-map values")
+	}
+}
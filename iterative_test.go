@@ -0,0 +1,69 @@
+package mxj
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestXmlIterativeMarshal(t *testing.T) {
+	fmt.Println("\n------------ iterative_test.go")
+	PrependAttrWithHyphen(true)
+	defer func() { XmlIterativeMarshal = false }()
+
+	docs := [][]byte{
+		[]byte(`<doc><elem1 name="elem1" seq="1"><sub1 name="sub1">sub_value_1</sub1><sub2/></elem1><elem2 seq="2">element_2</elem2></doc>`),
+		[]byte(`<doc><list><item>1</item><item>2</item><item><sub>3</sub></item></list><empty></empty><text attr="a">text<sub>x</sub></text></doc>`),
+		[]byte(`<books><book seq="1"><author>William T. Gaddis</author><review>Great.</review></book><book seq="2"><author>Austin Tappan Wright</author></book></books>`),
+	}
+	for _, d := range docs {
+		m, err := NewMapXml(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		XmlIterativeMarshal = false
+		x1, err := m.Xml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		i1, err := m.XmlIndent("  ", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		XmlIterativeMarshal = true
+		x2, err := m.Xml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		i2, err := m.XmlIndent("  ", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(x1, x2) {
+			t.Fatalf("Xml():\nrecursive: %s\niterative: %s", string(x1), string(x2))
+		}
+		if !bytes.Equal(i1, i2) {
+			t.Fatalf("XmlIndent():\nrecursive:\n%s\niterative:\n%s", string(i1), string(i2))
+		}
+	}
+
+	// a deeply nested Map value
+	XmlIterativeMarshal = true
+	depth := 10000
+	m := Map{}
+	n := map[string]interface{}(m)
+	for i := 0; i < depth; i++ {
+		nn := make(map[string]interface{})
+		n["a"] = nn
+		n = nn
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Repeat("<a>", depth-1) + "<a/>" + strings.Repeat("</a>", depth-1)
+	if string(x) != want {
+		t.Fatal("deeply nested Map not encoded correctly")
+	}
+}
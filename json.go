@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 )
 
@@ -23,7 +24,7 @@ func (mv Map) Json(safeEncoding ...bool) ([]byte, error) {
 		s = safeEncoding[0]
 	}
 
-	b, err := json.Marshal(mv)
+	b, err := json.Marshal(map[string]interface{}(mv))
 
 	if !s {
 		b = bytes.Replace(b, []byte("\\u003c"), []byte("<"), -1)
@@ -42,7 +43,7 @@ func (mv Map) JsonIndent(prefix, indent string, safeEncoding ...bool) ([]byte, e
 		s = safeEncoding[0]
 	}
 
-	b, err := json.MarshalIndent(mv, prefix, indent)
+	b, err := json.MarshalIndent(map[string]interface{}(mv), prefix, indent)
 	if !s {
 		b = bytes.Replace(b, []byte("\\u003c"), []byte("<"), -1)
 		b = bytes.Replace(b, []byte("\\u003e"), []byte(">"), -1)
@@ -51,6 +52,42 @@ func (mv Map) JsonIndent(prefix, indent string, safeEncoding ...bool) ([]byte, e
 	return b, err
 }
 
+// JsonNoEscape encodes the Map as JSON with HTML escaping disabled - see
+// encoding/json#Encoder.SetEscapeHTML - so '<', '>' and '&' are written literally.
+// Unlike mv.Json(), the text is not post-processed; so a value with the literal text
+// "\u003c" is encoded as is.
+func (mv Map) JsonNoEscape() ([]byte, error) {
+	b := new(bytes.Buffer)
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(map[string]interface{}(mv)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// JsonInto appends the Map encoded as JSON, as with mv.Json(), to 'buf'. Encoding many
+// Maps into a reused buffer - e.g., one that is Reset() after each is written, or taken
+// from a sync.Pool - avoids allocating each encoded value, reducing GC pressure.
+// As with mv.JsonNoEscape(), if 'safeEncoding' is not 'true' HTML escaping is disabled
+// rather than the text being post-processed. On error 'buf' is not modified.
+func (mv Map) JsonInto(buf *bytes.Buffer, safeEncoding ...bool) error {
+	var s bool
+	if len(safeEncoding) == 1 {
+		s = safeEncoding[0]
+	}
+
+	n := buf.Len()
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(s)
+	if err := enc.Encode(map[string]interface{}(mv)); err != nil {
+		buf.Truncate(n)
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // the Encode newline
+	return nil
+}
+
 // The following implementation is provided for symmetry with NewMapJsonReader[Raw]
 // The names will also provide a key for the number of return arguments.
 
@@ -102,6 +139,103 @@ func (mv Map) JsonIndentWriterRaw(jsonWriter io.Writer, prefix, indent string, s
 	return b, err
 }
 
+// JsonLines writes the values for 'path' on the Writer as JSON Lines - each value is
+// encoded as compact JSON followed by a newline. If the value for 'path' is a list,
+// []interface{}, each member is written on its own line; see mv.ValuesForPath().
+// If 'path' does not exist, nothing is written and PathNotExistError is returned.
+// If 'safeEncoding' is 'true', then "safe" encoding of '<', '>' and '&' is preserved.
+func (mv Map) JsonLines(path string, w io.Writer, safeEncoding ...bool) error {
+	var s bool
+	if len(safeEncoding) == 1 {
+		s = safeEncoding[0]
+	}
+
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return err
+	}
+	if len(vals) == 0 {
+		return PathNotExistError
+	}
+	for _, v := range vals {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if !s {
+			b = bytes.Replace(b, []byte("\\u003c"), []byte("<"), -1)
+			b = bytes.Replace(b, []byte("\\u003e"), []byte(">"), -1)
+			b = bytes.Replace(b, []byte("\\u0026"), []byte("&"), -1)
+		}
+		if _, err = w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JsonForceArrays encodes the Map as JSON, as with mv.Json(), with the values for the
+// 'paths' encoded as arrays even if they are not lists - e.g., an element that occurs
+// once in the XML doc - so that the JSON is consistent with a schema that declares them
+// arrays. Thus, with 'paths' as "doc.book", {"doc":{"book":{"title":"a"}}} is encoded as
+//	{"doc":{"book":[{"title":"a"}]}}
+// The 'paths' are dot-separated keys, such as "doc.books.book" or "doc.*.-lang"; a "*"
+// matches any key and lists in the path are traversed, so all the matching values are
+// encoded as arrays. Paths that don't exist are ignored. The Map is not modified.
+func (mv Map) JsonForceArrays(paths ...string) ([]byte, error) {
+	m := copyValue(map[string]interface{}(mv)).(map[string]interface{})
+	for _, p := range paths {
+		forceArrays(m, strings.Split(p, "."))
+	}
+	return Map(m).Json()
+}
+
+// forceArrays wraps the values for 'keys' beneath 'v' in a []interface{} value.
+func forceArrays(v interface{}, keys []string) {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		for k, vv := range m {
+			if keys[0] != "*" && keys[0] != k {
+				continue
+			}
+			if len(keys) > 1 {
+				forceArrays(vv, keys[1:])
+				continue
+			}
+			if _, ok := vv.([]interface{}); !ok {
+				m[k] = []interface{}{vv}
+			}
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			forceArrays(vv, keys)
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler, so Map values that are members of
+// structures, etc., are encoded consistently with mv.Json(true).
+func (mv Map) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}(mv))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, so Map values that are members of
+// structures, etc., are decoded as with NewMapJson() - e.g., JsonUseNumber is
+// recognized. A JSON 'null' value decodes as a 'nil' Map.
+func (mv *Map) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) == "null" {
+		*mv = nil
+		return nil
+	}
+	m, err := NewMapJson(b)
+	if err != nil {
+		return err
+	}
+	*mv = m
+	return nil
+}
+
 // --------------------------- read JSON -----------------------------
 
 // Decode numericvalues as json.Number type Map values - see encoding/json#Number.
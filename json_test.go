@@ -2,6 +2,7 @@ package mxj
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"testing"
@@ -135,3 +136,189 @@ func TestJsonWriter(t *testing.T) {
 	fmt.Println("JsonWriter, raw:", string(raw))
 	fmt.Println("JsonWriter, b  :", string(b))
 }
+
+func TestMapMarshalJSON(t *testing.T) {
+	type doc struct {
+		Name string
+		Data Map
+		Nil  Map
+	}
+	d := doc{Name: "test", Data: Map{"a": "1", "b": map[string]interface{}{"c": 2.5}}}
+	j, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Name":"test","Data":{"a":"1","b":{"c":2.5}},"Nil":null}`
+	if string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+
+	JsonUseNumber = true
+	defer func() { JsonUseNumber = false }()
+	var dd doc
+	if err = json.Unmarshal(j, &dd); err != nil {
+		t.Fatal(err)
+	}
+	if dd.Nil != nil {
+		t.Fatal("Nil:", dd.Nil)
+	}
+	v, err := dd.Data.ValueForPath("b.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(json.Number) != "2.5" {
+		t.Fatal("b.c:", v)
+	}
+}
+
+func TestJsonNoEscape(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"text": "<a> & <b>", "lit": `\u003c`}}
+	j, err := m.JsonNoEscape()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"doc":{"lit":"\\u003c","text":"<a> & <b>"}}`; string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+	mm, err := NewMapJson(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := mm.ValueForPath("doc.lit"); v != `\u003c` {
+		t.Fatal("doc.lit:", v)
+	}
+}
+
+func TestJsonWithTypes(t *testing.T) {
+	m, err := NewMapXml([]byte(`<doc><id>123</id><ok>true</ok><name>x</name><list>1</list><list>a</list></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := m.JsonWithTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"__types__":{"doc.id":"number","doc.list[0]":"number","doc.list[1]":"string","doc.name":"string","doc.ok":"boolean"},` +
+		`"doc":{"id":"123","list":["1","a"],"name":"x","ok":"true"}}`
+	if string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+	if _, ok := m[JsonTypesKey]; ok {
+		t.Fatal("Map modified")
+	}
+
+	m = Map{"n": nil, "f": 1.5, JsonTypesKey: "x"}
+	if _, err = m.JsonWithTypes(); err == nil {
+		t.Fatal("no error for JsonTypesKey key")
+	}
+	delete(m, JsonTypesKey)
+	if j, _ = m.JsonWithTypes(); string(j) != `{"__types__":{"f":"number","n":"null"},"f":1.5,"n":null}` {
+		t.Fatal("got:", string(j))
+	}
+}
+
+func TestJsonLines(t *testing.T) {
+	m := Map{"feed": map[string]interface{}{
+		"entry": []interface{}{
+			map[string]interface{}{"id": 1, "title": "a&b"},
+			map[string]interface{}{"id": 2},
+		},
+		"title": "x",
+	}}
+	b := new(bytes.Buffer)
+	if err := m.JsonLines("feed.entry", b); err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"id\":1,\"title\":\"a&b\"}\n{\"id\":2}\n"
+	if b.String() != want {
+		t.Fatal("got:", b.String(), "want:", want)
+	}
+
+	b.Reset()
+	if err := m.JsonLines("feed.title", b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "\"x\"\n" {
+		t.Fatal("got:", b.String())
+	}
+
+	b.Reset()
+	if err := m.JsonLines("feed.none", b); err != PathNotExistError {
+		t.Fatal("no PathNotExistError returned:", err)
+	}
+	if b.Len() != 0 {
+		t.Fatal("written:", b.String())
+	}
+}
+
+func TestJsonForceArrays(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{
+		"book": map[string]interface{}{"-lang": "en", "title": "a"},
+		"shelf": []interface{}{
+			map[string]interface{}{"item": "x"},
+			map[string]interface{}{"item": []interface{}{"y", "z"}},
+		},
+		"note": "n",
+	}}
+	b, err := m.JsonForceArrays("doc.book", "doc.shelf.item", "doc.*.-lang", "doc.none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"doc":{"book":[{"-lang":["en"],"title":"a"}],"note":"n",` +
+		`"shelf":[{"item":["x"]},{"item":["y","z"]}]}}`
+	if string(b) != want {
+		t.Fatal("got:", string(b), "want:", want)
+	}
+	if _, ok := m["doc"].(map[string]interface{})["book"].(map[string]interface{}); !ok {
+		t.Fatal("Map modified:", m)
+	}
+}
+
+func TestJsonInto(t *testing.T) {
+	m, err := NewMapJson(jdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.NewBufferString("prefix:")
+	for _, safe := range []bool{false, true} {
+		buf.Truncate(len("prefix:"))
+		if err = m.JsonInto(buf, safe); err != nil {
+			t.Fatal(err)
+		}
+		want, _ := m.Json(safe)
+		if buf.String() != "prefix:"+string(want) {
+			t.Fatal("got:", buf.String(), "want:", string(want))
+		}
+	}
+
+	bad := Map{"f": func() {}}
+	n := buf.Len()
+	if err = bad.JsonInto(buf); err == nil {
+		t.Fatal("no error for func value")
+	}
+	if buf.Len() != n {
+		t.Fatal("buf modified:", buf.String())
+	}
+}
+
+func BenchmarkJson(b *testing.B) {
+	m, _ := NewMapJson(jdata)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := m.Json(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJsonInto(b *testing.B) {
+	m, _ := NewMapJson(jdata)
+	buf := new(bytes.Buffer)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := m.JsonInto(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
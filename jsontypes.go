@@ -0,0 +1,60 @@
+// jsontypes.go - encode a Map as JSON with the inferred types of its values.
+
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JsonTypesKey is the key of the type map added by mv.JsonWithTypes().
+var JsonTypesKey = "__types__"
+
+// JsonWithTypes encodes the Map as JSON, as with mv.Json(), with an additional top level
+// JsonTypesKey object that has the inferred type of each leaf value keyed by its
+// LeafNode.Path - see mv.LeafNodes(). The types are the JSON type names: "string",
+// "number", "boolean" and "null". A string value has the type that it would be cast
+// to by NewMapXml(doc, Cast) - e.g., "123" is a "number" - so a consumer can restore
+// the types of a Map decoded from XML without casting:
+//	<doc><id>123</id><ok>true</ok><name>x</name></doc>
+// encodes as:
+//	{"__types__":{"doc.id":"number","doc.name":"string","doc.ok":"boolean"},
+//	 "doc":{"id":"123","name":"x","ok":"true"}}
+// Error is returned if the Map already has a JsonTypesKey key.
+func (mv Map) JsonWithTypes(safeEncoding ...bool) ([]byte, error) {
+	if _, ok := mv[JsonTypesKey]; ok {
+		return nil, fmt.Errorf("JsonWithTypes: Map has %s key", JsonTypesKey)
+	}
+	leaves := mv.LeafNodes()
+	types := make(map[string]interface{}, len(leaves))
+	for _, v := range leaves {
+		types[v.Path] = jsonType(v.Value)
+	}
+	m := make(map[string]interface{}, len(mv)+1)
+	for k, v := range mv {
+		m[k] = v
+	}
+	m[JsonTypesKey] = types
+	return Map(m).Json(safeEncoding...)
+}
+
+// jsonType returns the JSON type name of a Map leaf value.
+func jsonType(v interface{}) string {
+	if s, ok := v.(string); ok {
+		v = cast(s, true, "")
+	}
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string, []byte:
+		return "string"
+	case json.Number:
+		return "number"
+	}
+	if _, ok := numberValue(v); ok {
+		return "number"
+	}
+	return "string"
+}
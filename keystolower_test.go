@@ -58,3 +58,22 @@ func TestToLower(t *testing.T) {
 		}
 	}
 }
+
+func TestLowercaseKeys(t *testing.T) {
+	LowercaseKeys = true
+	defer func() { LowercaseKeys = false }()
+	SetAttrPrefix("A_")
+	defer SetAttrPrefix("-")
+
+	m, err := NewMapXml([]byte(`<Doc><Element Attr="1">x</Element></Doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := m.ValueForPath("doc.element.A_attr")
+	if err != nil {
+		t.Fatal(err, m)
+	}
+	if v.(string) != "1" {
+		t.Fatal("doc.element.A_attr:", v)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ----------------------------- get everything FOR a single key -------------------------
@@ -57,7 +58,38 @@ func (mv Map) ValuesForKey(key string, subkeys ...string) ([]interface{}, error)
 
 	ret := make([]interface{}, 0, defaultArraySize)
 	var cnt int
-	hasKey(m, key, &ret, &cnt, subKeyMap)
+	hasKey(m, key, &ret, &cnt, subKeyMap, -1)
+	for i, v := range ret[:cnt] {
+		ret[i] = simpleSourceValue(v)
+	}
+	return ret[:cnt], nil
+}
+
+// ValuesForKeyLimited is ValuesForKey, except that the Map is not searched below 'maxDepth'
+// levels of map[string]interface{} values - the top level keys of 'mv' are at depth 1; the
+// members of a list are at the depth of the list, except for lists of lists, which are
+// a level deeper for each nested list. This bounds the descent for a Map decoded from untrusted input,
+// which may be nested deeply enough to exhaust the goroutine stack. If maxDepth < 1, an
+// empty list is returned.
+func (mv Map) ValuesForKeyLimited(key string, maxDepth int, subkeys ...string) ([]interface{}, error) {
+	m := map[string]interface{}(mv)
+	var subKeyMap map[string]interface{}
+	if len(subkeys) > 0 {
+		var err error
+		subKeyMap, err = getSubKeyMap(subkeys...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]interface{}, 0, defaultArraySize)
+	var cnt int
+	if maxDepth > 0 {
+		hasKey(m, key, &ret, &cnt, subKeyMap, maxDepth)
+	}
+	for i, v := range ret[:cnt] {
+		ret[i] = simpleSourceValue(v)
+	}
 	return ret[:cnt], nil
 }
 
@@ -78,10 +110,14 @@ func (mv Map) ValueForKey(key string, subkeys ...string) (interface{}, error) {
 
 // hasKey - if the map 'key' exists append it to array
 //          if it doesn't do nothing except scan array and map values
-func hasKey(iv interface{}, key string, ret *[]interface{}, cnt *int, subkeys map[string]interface{}) {
+// 'depth' is the number of map levels to search; if < 0, the search is not limited.
+func hasKey(iv interface{}, key string, ret *[]interface{}, cnt *int, subkeys map[string]interface{}, depth int) {
 	// func hasKey(iv interface{}, key string, ret *[]interface{}, subkeys map[string]interface{}) {
 	switch iv.(type) {
 	case map[string]interface{}:
+		if depth == 0 {
+			return
+		}
 		vv := iv.(map[string]interface{})
 		// see if the current value is of interest
 		if v, ok := vv[key]; ok {
@@ -133,11 +169,19 @@ func hasKey(iv interface{}, key string, ret *[]interface{}, cnt *int, subkeys ma
 
 		// scan the rest
 		for _, v := range vv {
-			hasKey(v, key, ret, cnt, subkeys)
+			hasKey(v, key, ret, cnt, subkeys, depth-1)
 		}
 	case []interface{}:
+		if depth == 0 {
+			return
+		}
 		for _, v := range iv.([]interface{}) {
-			hasKey(v, key, ret, cnt, subkeys)
+			// a list in a list is a level, too - e.g., decoded JSON [[[...]]]
+			if _, ok := v.([]interface{}); ok && depth > 0 {
+				hasKey(v, key, ret, cnt, subkeys, depth-1)
+				continue
+			}
+			hasKey(v, key, ret, cnt, subkeys, depth)
 		}
 	}
 }
@@ -165,7 +209,18 @@ func hasKey(iv interface{}, key string, ret *[]interface{}, cnt *int, subkeys ma
 //             - If a subkey is preceeded with the '!' character, the key:value[:type] entry is treated as an
 //               exclusion critera - e.g., "!author:William T. Gaddis".
 //             - If val contains ":" symbol, use SetFieldSeparator to a unused symbol, perhaps "|".
+//   The "#line", "#col" and "#raw" annotations of simple elements - see DecodeSourcePositions
+//   and DecodeSourceBytes - are stepped over; the element's text value is returned.
 func (mv Map) ValuesForPath(path string, subkeys ...string) ([]interface{}, error) {
+	vals, err := mv.valuesForPath(path, subkeys...)
+	for i, v := range vals {
+		vals[i] = simpleSourceValue(v)
+	}
+	return vals, err
+}
+
+// valuesForPath is ValuesForPath without stepping over the source annotations.
+func (mv Map) valuesForPath(path string, subkeys ...string) ([]interface{}, error) {
 	// If there are no array indexes in path, use legacy ValuesForPath() logic.
 	if strings.Index(path, "[") < 0 {
 		return mv.oldValuesForPath(path, subkeys...)
@@ -647,6 +702,91 @@ func (mv Map) ValueForPath(path string) (interface{}, error) {
 	return vals[0], nil
 }
 
+// UniqueValueForPath is ValueForPath for a path that is expected to match exactly one value.
+// If no value is found it returns 'nil' and PathNotExistError; if 'path' - e.g., with
+// wildcards or through a list - matches more than one value it returns 'nil' and
+// PathNotUniqueError.
+func (mv Map) UniqueValueForPath(path string) (interface{}, error) {
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	switch len(vals) {
+	case 0:
+		return nil, PathNotExistError
+	case 1:
+		return vals[0], nil
+	}
+	return nil, PathNotUniqueError
+}
+
+// DeepGet returns the value for the exact 'path' and whether it exists, without the
+// allocations of mv.ValueForPath() - for extracting values in performance sensitive
+// loops. The 'path' is dot-separated keys, such as "doc.books.book[1].title"; wildcards,
+// "*", are not supported. As with mv.ValueForPath(), a list in the path without an index
+// is searched in order for a member with the rest of the path, and a value that is not
+// a list is member [0]. Unlike mv.ValueForPath(), a list value for 'path' is returned
+// as the []interface{} value, not its first member.
+func (mv Map) DeepGet(path string) (interface{}, bool) {
+	return deepGet(map[string]interface{}(mv), path)
+}
+
+func deepGet(v interface{}, path string) (interface{}, bool) {
+	for path != "" {
+		seg := path // the rest of the path, including the key
+		k := path
+		path = ""
+		if i := strings.IndexByte(k, '.'); i >= 0 {
+			k, path = k[:i], k[i+1:]
+		}
+		if k == "" {
+			continue
+		}
+		index := -1
+		if i := strings.IndexByte(k, '['); i >= 0 {
+			if k[len(k)-1] != ']' {
+				return nil, false
+			}
+			n, err := strconv.Atoi(k[i+1 : len(k)-1])
+			if err != nil || n < 0 {
+				return nil, false
+			}
+			k, index = k[:i], n
+		}
+
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			if v = vv[k]; v == nil {
+				if _, ok := vv[k]; !ok {
+					return nil, false
+				}
+			}
+		case []interface{}:
+			// the first member with the rest of the path
+			for _, m := range vv {
+				if r, ok := deepGet(m, seg); ok {
+					return r, true
+				}
+			}
+			return nil, false
+		default:
+			return nil, false
+		}
+
+		if index >= 0 {
+			if a, ok := v.([]interface{}); ok {
+				if index >= len(a) {
+					return nil, false
+				}
+				v = a[index]
+			} else if index != 0 {
+				return nil, false
+			}
+		}
+	}
+	return simpleSourceValue(v), true
+}
+
 // ValuesForPathString returns the first found value for the path as a string.
 func (mv Map) ValueForPathString(path string) (string, error) {
 	vals, err := mv.ValuesForPath(path)
@@ -666,3 +806,25 @@ func (mv Map) ValueOrEmptyForPathString(path string) string {
 	str, _ := mv.ValueForPathString(path)
 	return str
 }
+
+// Time returns the first found value for the path as a time.Time value. A string value
+// is parsed per 'layout' - see time.Parse - after leading and trailing white space is
+// trimmed; if 'layout' is "", time.RFC3339 is used. A time.Time value - e.g., from
+// NewMapStruct() or NewMapToml() - is returned as is.
+// If no value is found it returns PathNotExistError.
+func (mv Map) Time(path, layout string) (time.Time, error) {
+	v, err := mv.ValueForPath(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	switch v.(type) {
+	case time.Time:
+		return v.(time.Time), nil
+	case string:
+		return time.Parse(layout, strings.TrimSpace(v.(string)))
+	}
+	return time.Time{}, fmt.Errorf("Time: value for path is not a string: %s: %T", path, v)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	// "io"
 	"testing"
+	"time"
 )
 
 func TestKVHeader(t *testing.T) {
@@ -459,6 +460,33 @@ func TestValueForPathError(t *testing.T) {
 	}
 }
 
+func TestUniqueValueForPath(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{
+		"book": []interface{}{
+			map[string]interface{}{"title": "a", "seq": "1"},
+			map[string]interface{}{"title": "b"},
+		},
+		"author": "x",
+	}}
+
+	v, err := m.UniqueValueForPath("doc.book.seq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "1" {
+		t.Fatal("doc.book.seq:", v)
+	}
+	if _, err = m.UniqueValueForPath("doc.book.title"); err != PathNotUniqueError {
+		t.Fatal("doc.book.title: no PathNotUniqueError returned:", err)
+	}
+	if _, err = m.UniqueValueForPath("doc.*"); err != PathNotUniqueError {
+		t.Fatal("doc.*: no PathNotUniqueError returned:", err)
+	}
+	if _, err = m.UniqueValueForPath("doc.editor"); err != PathNotExistError {
+		t.Fatal("doc.editor: no PathNotExistError returned:", err)
+	}
+}
+
 func TestValueForKey(t *testing.T) {
 	m := map[string]interface{}{
 		"Div": map[string]interface{}{
@@ -490,3 +518,121 @@ func TestValueForKeyError(t *testing.T) {
 	}
 }
 
+func TestValuesForKeyLimited(t *testing.T) {
+	m := Map{"a": map[string]interface{}{
+		"id": "1",
+		"b": []interface{}{
+			map[string]interface{}{"id": "2"},
+			map[string]interface{}{"c": map[string]interface{}{"id": "3"}},
+		}}}
+	for depth, want := range map[int]int{0: 0, 1: 0, 2: 1, 3: 2, 4: 3, 100: 3} {
+		vals, err := m.ValuesForKeyLimited("id", depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != want {
+			t.Fatal("depth:", depth, "vals:", vals)
+		}
+	}
+
+	// deeply nested input doesn't exhaust the stack
+	deep := map[string]interface{}{"id": "x"}
+	for i := 0; i < 100000; i++ {
+		deep = map[string]interface{}{"n": deep}
+	}
+	vals, _ := Map(deep).ValuesForKeyLimited("id", 1000)
+	if len(vals) != 0 {
+		t.Fatal("deep vals:", vals)
+	}
+
+	// and nor do deeply nested lists
+	var list interface{} = []interface{}{map[string]interface{}{"id": "x"}}
+	for i := 0; i < 100000; i++ {
+		list = []interface{}{list}
+	}
+	vals, _ = Map{"n": list}.ValuesForKeyLimited("id", 1000)
+	if len(vals) != 0 {
+		t.Fatal("deep list vals:", vals)
+	}
+	vals, _ = Map{"n": []interface{}{[]interface{}{map[string]interface{}{"id": "x"}}}}.ValuesForKeyLimited("id", 3)
+	if len(vals) != 1 {
+		t.Fatal("list vals:", vals)
+	}
+}
+
+func TestMapTime(t *testing.T) {
+	m, err := NewMapXml([]byte(`<doc><ts>2020-03-04T05:06:07Z</ts><day> 04/03/2020 </day><n>5</n></doc>`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := m.Time("doc.ts", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ts.Equal(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)) {
+		t.Fatal("doc.ts:", ts)
+	}
+	day, err := m.Time("doc.day", "02/01/2006")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !day.Equal(time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("doc.day:", day)
+	}
+	if _, err = m.Time("doc.day", ""); err == nil {
+		t.Fatal("no error for layout mismatch")
+	}
+	if _, err = m.Time("doc.n", ""); err == nil {
+		t.Fatal("no error for number value")
+	}
+	if _, err = m.Time("doc.none", ""); err != PathNotExistError {
+		t.Fatal("no PathNotExistError returned:", err)
+	}
+	if v, err := (Map{"t": ts}).Time("t", ""); err != nil || !v.Equal(ts) {
+		t.Fatal("time.Time value:", v, err)
+	}
+}
+
+func TestDeepGet(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{
+		"books": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "a"},
+				map[string]interface{}{"title": "b", "isbn": "123"},
+			},
+		},
+		"one":  map[string]interface{}{"title": "c"},
+		"none": nil,
+	}}
+	checks := []struct {
+		path string
+		want interface{}
+	}{
+		{"doc.books.book[1].title", "b"},
+		{"doc.books.book.isbn", "123"},
+		{"doc.books.book.title", "a"},
+		{"doc.one[0].title", "c"},
+		{"doc.none", nil},
+	}
+	for _, c := range checks {
+		v, ok := m.DeepGet(c.path)
+		if !ok || v != c.want {
+			t.Fatal(c.path, "got:", v, ok, "want:", c.want)
+		}
+		if vv, _ := m.ValueForPath(c.path); vv != v {
+			t.Fatal(c.path, "ValueForPath:", vv)
+		}
+	}
+	if v, ok := m.DeepGet("doc.books.book"); !ok || len(v.([]interface{})) != 2 {
+		t.Fatal("doc.books.book:", v, ok)
+	}
+	for _, p := range []string{"doc.x", "doc.books.book[2]", "doc.one[1]", "doc.one.title.x", "doc.books.book[a]", "doc.*"} {
+		if v, ok := m.DeepGet(p); ok {
+			t.Fatal(p, "found:", v)
+		}
+	}
+
+	if n := testing.AllocsPerRun(100, func() { m.DeepGet("doc.books.book.isbn") }); n != 0 {
+		t.Fatal("allocations:", n)
+	}
+}
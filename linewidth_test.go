@@ -0,0 +1,41 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestXmlIndentMaxLineWidth(t *testing.T) {
+	fmt.Println("\n------------ linewidth_test.go")
+	PrependAttrWithHyphen(true)
+	SetXmlIndentMaxLineWidth(30)
+	defer SetXmlIndentMaxLineWidth(0)
+
+	m := Map{"doc": map[string]interface{}{
+		"short": map[string]interface{}{"-id": "1", "#text": "a"},
+		"long":  map[string]interface{}{"-id": "2", "-name": "a long attribute value", "#text": "b"},
+	}}
+	x, err := m.XmlIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc>
+  <long
+    id="2"
+    name="a long attribute value">b</long>
+  <short id="1">a</short>
+</doc>`
+	if string(x) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", string(x), want)
+	}
+
+	// no wrapping if not indenting
+	x, err = m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `<doc><long id="2" name="a long attribute value">b</long><short id="1">a</short></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
@@ -0,0 +1,70 @@
+// mapchan.go - read XML messages from an io.Reader on a channel.
+
+package mxj
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// MapResult is a value received from the XmlReaderToMapChan channel; either Map is the
+// next decoded message or Err is the error that stopped the reading.
+type MapResult struct {
+	Map Map
+	Err error
+}
+
+// XmlReaderToMapChan decodes the XML messages on 'xmlReader' - as with NewMapXmlReader -
+// in a goroutine and sends them on the returned channel, which has a buffer of 'bufSize'
+// values. When the buffer is full the goroutine blocks, so no more than 'bufSize'+1
+// messages are read ahead of the receiver. The channel is closed at io.EOF, after a
+// MapResult with a non-nil Err is sent - use HandleXmlReader to continue past errors -
+// or after the returned cancel function is called; cancel can be called more than once.
+//	NOTE: cancel does not interrupt a blocked xmlReader.Read() call; the goroutine stops
+//	      when the read returns.
+func XmlReaderToMapChan(xmlReader io.Reader, bufSize int) (<-chan MapResult, func()) {
+	if bufSize < 0 {
+		bufSize = 0
+	}
+	c := make(chan MapResult, bufSize)
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() { once.Do(func() { close(done) }) }
+
+	go func() {
+		defer close(c)
+		var n int
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			m, err := NewMapXmlReader(xmlReader)
+			n++
+			if err == io.EOF {
+				return
+			}
+			var r MapResult
+			if err != nil {
+				r.Err = fmt.Errorf("[xmlReader: %d] %s", n, err.Error())
+			} else if len(m) == 0 {
+				time.Sleep(xhandlerPollInterval) // see HandleXmlReader
+				continue
+			} else {
+				r.Map = m
+			}
+			select {
+			case c <- r:
+			case <-done:
+				return
+			}
+			if r.Err != nil {
+				return
+			}
+		}
+	}()
+	return c, cancel
+}
@@ -0,0 +1,58 @@
+package mxj
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestXmlReaderToMapChan(t *testing.T) {
+	fmt.Println("\n------------ mapchan_test.go")
+	data := strings.Repeat(`<msg><n>1</n></msg>`, 10)
+	c, cancel := XmlReaderToMapChan(strings.NewReader(data), 2)
+	defer cancel()
+	var n int
+	for r := range c {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		if v, _ := r.Map.ValueForPath("msg.n"); v != "1" {
+			t.Fatal("msg.n:", v)
+		}
+		n++
+	}
+	if n != 10 {
+		t.Fatal("n:", n)
+	}
+
+	// backpressure and cancel - the bytes.Reader shows how far ahead it is read
+	rdr := bytes.NewReader([]byte(strings.Repeat(`<msg><n>1</n></msg>`, 100)))
+	c, cancel = XmlReaderToMapChan(rdr, 1)
+	r := <-c
+	if r.Err != nil {
+		t.Fatal(r.Err)
+	}
+	cancel()
+	cancel()
+	for range c {
+	}
+	if read := rdr.Size() - int64(rdr.Len()); read > 5*int64(len(`<msg><n>1</n></msg>`)) {
+		t.Fatal("read ahead:", read)
+	}
+
+	// error
+	c, cancel = XmlReaderToMapChan(strings.NewReader(`<msg><n>1</n></msg><bad></msg><msg/>`), 0)
+	defer cancel()
+	var errs, maps int
+	for r := range c {
+		if r.Err != nil {
+			errs++
+		} else {
+			maps++
+		}
+	}
+	if errs != 1 || maps != 1 {
+		t.Fatal("errs:", errs, "maps:", maps)
+	}
+}
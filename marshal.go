@@ -0,0 +1,113 @@
+// marshal.go - encode a Map as XML with per-call settings.
+
+package mxj
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+)
+
+// EncodeOptions are the encoding settings for mv.Marshal(). They apply only to
+// the call; the package settings - XMLEscapeChars(), XmlGoEmptyElemSyntax(), etc. -
+// are not used or modified, so concurrent encoding with different settings is safe.
+// The zero value gives the package defaults; NewEncodeOptions() gives the current
+// package settings.
+//	RootTag           - the root tag, as with mv.Xml(rootTag).
+//	Prefix, Indent    - if either is not "", the XML is encoded as with mv.XmlIndent().
+//	MaxLineWidth      - see SetXmlIndentMaxLineWidth(); only for indented XML.
+//	CDATAPaths        - element paths with CDATA text, see mv.XmlWithCDATA().
+//	WrapArrays        - container tags for list keys, see mv.XmlWrapArrays().
+//	MemberTags        - member tags for list keys, see mv.XmlMemberTags().
+//	NilPolicy         - the encoding of nil values, see mv.XmlWithNilPolicy().
+//	AttrPrecision     - see SetXmlAttrFloatPrecision().
+//	Header            - precede the XML with an XML declaration, see mv.XmlWithHeader().
+//	Encoding          - the XML declaration encoding; if "", XmlHeaderEncoding is used.
+//	EscapeChars       - see XMLEscapeChars().
+//	EscapeMode        - see SetXmlEscapeMode().
+//	GoEmptyElemSyntax - see XmlGoEmptyElemSyntax().
+//	OmitEmptySlices   - see XmlEmitEmptySlices(false).
+//	TrailingNewline   - see XmlTrailingNewline().
+//	CheckIsValid      - see XmlCheckIsValid().
+type EncodeOptions struct {
+	RootTag           string
+	Prefix            string
+	Indent            string
+	MaxLineWidth      int
+	CDATAPaths        []string
+	WrapArrays        map[string]string
+	MemberTags        map[string]string
+	NilPolicy         NilPolicy
+	AttrPrecision     int
+	Header            bool
+	Encoding          string
+	EscapeChars       bool
+	EscapeMode        XmlEscapeMode
+	GoEmptyElemSyntax bool
+	OmitEmptySlices   bool
+	TrailingNewline   bool
+	CheckIsValid      bool
+}
+
+// NewEncodeOptions returns EncodeOptions initialized with the current package settings.
+func NewEncodeOptions() EncodeOptions {
+	return EncodeOptions{
+		MaxLineWidth:      xmlIndentMaxLineWidth,
+		AttrPrecision:     xmlAttrFloatPrecision,
+		EscapeChars:       xmlEscapeChars,
+		EscapeMode:        xmlEscapeMode,
+		GoEmptyElemSyntax: useGoXmlEmptyElemSyntax,
+		OmitEmptySlices:   !xmlEmitEmptySlices,
+		TrailingNewline:   xmlTrailingNewline,
+		CheckIsValid:      xmlCheckIsValid,
+	}
+}
+
+// Marshal encodes the Map as XML using the settings in 'opts' rather than the
+// package settings. See mv.Xml() for encoding rules.
+func (mv Map) Marshal(opts EncodeOptions) ([]byte, error) {
+	p := new(pretty)
+	p.opts = &opts
+	if len(opts.CDATAPaths) > 0 {
+		p.cdata = cdataPaths(opts.CDATAPaths)
+	}
+	p.wrap = opts.WrapArrays
+	p.members = opts.MemberTags
+	p.nilPolicy = opts.NilPolicy
+	var rootTag []string
+	if opts.RootTag != "" {
+		rootTag = []string{opts.RootTag}
+	}
+
+	var x []byte
+	var err error
+	if opts.Prefix == "" && opts.Indent == "" {
+		if x, err = mv.xml(p, rootTag...); err != nil {
+			return nil, err
+		}
+	} else {
+		p.indent = opts.Indent
+		p.padding = opts.Prefix
+		p.maxLineWidth = opts.MaxLineWidth
+		b := new(bytes.Buffer)
+		if err = marshalMapToXmlIndentRoot(b, map[string]interface{}(mv), p, rootTag...); err != nil {
+			return nil, err
+		}
+		x = b.Bytes()
+		if opts.CheckIsValid {
+			d := xml.NewDecoder(bytes.NewReader(x))
+			for {
+				if _, err = d.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if opts.Header {
+		x = append(xmlHeader(opts.Encoding), x...)
+	}
+	return x, nil
+}
@@ -0,0 +1,111 @@
+package mxj
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	fmt.Println("\n------------ marshal_test.go")
+	PrependAttrWithHyphen(true)
+
+	m := Map{"doc": map[string]interface{}{"-id": "1", "body": "a<b", "empty": "", "list": []interface{}{}}}
+
+	x, err := m.Marshal(EncodeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc id="1"><body>a<b</body><empty/><list/></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	opts := EncodeOptions{
+		RootTag:           "root",
+		Header:            true,
+		EscapeChars:       true,
+		GoEmptyElemSyntax: true,
+		OmitEmptySlices:   true,
+	}
+	x, err = m.Marshal(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<root><doc id="1"><body>a&lt;b</body><empty></empty></doc></root>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// indented - same as XmlIndent with the same settings
+	m = Map{"doc": map[string]interface{}{"body": "<p>text</p>", "title": "t"}}
+	x, err = m.Marshal(EncodeOptions{Indent: "  ", CDATAPaths: []string{"doc.body"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	xi, _ := m.XmlIndentWithCDATA([]string{"doc.body"}, "", "  ")
+	if string(x) != string(xi) {
+		t.Fatal("got:", string(x), "want:", string(xi))
+	}
+
+	// the package settings are not used
+	XMLEscapeChars(true)
+	x, _ = m.Marshal(EncodeOptions{})
+	XMLEscapeChars(false)
+	if want := `<doc><body><p>text</p></body><title>t</title></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
+
+func TestGoEmptyElemSyntaxAttrs(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	m := Map{"doc": map[string]interface{}{"a": map[string]interface{}{"-x": "1"}, "b": ""}}
+	want := `<doc><a x="1"></a><b></b></doc>`
+
+	x, err := m.Marshal(EncodeOptions{GoEmptyElemSyntax: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	XmlGoEmptyElemSyntax()
+	defer XmlDefaultEmptyElemSyntax()
+	if x, err = m.Xml(); err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if x, err = m.XmlIndent("", "  "); err != nil {
+		t.Fatal(err)
+	}
+	if want = "<doc>\n  <a x=\"1\"></a>\n  <b></b>\n</doc>"; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
+
+func TestMarshalConcurrent(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"body": "a&b", "empty": ""}}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(escape bool) {
+			defer wg.Done()
+			x, err := m.Marshal(EncodeOptions{EscapeChars: escape, GoEmptyElemSyntax: escape})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			want := `<doc><body>a&b</body><empty/></doc>`
+			if escape {
+				want = `<doc><body>a&amp;b</body><empty></empty></doc>`
+			}
+			if string(x) != want {
+				t.Error("got:", string(x), "want:", want)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}
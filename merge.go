@@ -0,0 +1,120 @@
+// merge.go - merge the content of one Map into another.
+
+package mxj
+
+import "errors"
+
+// SliceStrategy is how mv.Merge() merges list values.
+type SliceStrategy struct {
+	mode int
+	key  string // for SliceByKey
+}
+
+const (
+	sliceConcat = iota
+	sliceReplace
+	sliceByIndex
+	sliceByKey
+)
+
+var (
+	SliceConcat  = SliceStrategy{mode: sliceConcat}  // append the 'src' members; the default
+	SliceReplace = SliceStrategy{mode: sliceReplace} // replace the list with the 'src' list
+	SliceByIndex = SliceStrategy{mode: sliceByIndex} // merge the members with the same index; append the rest
+)
+
+// SliceByKey merges the list members that are maps with equal values for 'key', appending
+// the 'src' members that don't match a member of the list. For attributes prefix the key
+// with the attribute prefix, by default a hyphen, '-', e.g., "-id". (See SetAttrPrefix.)
+func SliceByKey(key string) SliceStrategy {
+	return SliceStrategy{mode: sliceByKey, key: key}
+}
+
+// Merge merges the content of 'src' into mv, modifying mv in place:
+//	- Keys that are only in 'src' are added.
+//	- If both values are map[string]interface{}, the subelements are merged.
+//	- If either value is a list, []interface{}, the lists are merged per 'strategy'; a
+//	  value that is not a list is merged as a single member list, as it would be decoded
+//	  from XML. By default, SliceConcat, the 'src' members are appended.
+//	- Otherwise the 'src' value replaces the mv value.
+// Numeric key values for SliceByKey are compared without regard to their type - see
+// EqualExcept. The map[string]interface{} and []interface{} values from 'src' are copied,
+// so subsequent modification of 'src' does not modify mv.
+func (mv Map) Merge(src Map, strategy ...SliceStrategy) error {
+	if mv == nil && len(src) > 0 {
+		return errors.New("cannot merge into a nil Map")
+	}
+	s := SliceConcat
+	if len(strategy) == 1 {
+		s = strategy[0]
+	}
+	mergeMap(map[string]interface{}(mv), map[string]interface{}(src), s)
+	return nil
+}
+
+func mergeMap(m, src map[string]interface{}, s SliceStrategy) {
+	for k, sv := range src {
+		if v, ok := m[k]; ok {
+			m[k] = mergeValue(v, sv, s)
+			continue
+		}
+		m[k] = copyValue(sv)
+	}
+}
+
+// mergeValue returns the value 'v' with 'src' merged.
+func mergeValue(v, src interface{}, s SliceStrategy) interface{} {
+	a, aok := v.([]interface{})
+	sa, sok := src.([]interface{})
+	if !aok && !sok {
+		vm, vok := v.(map[string]interface{})
+		sm, smok := src.(map[string]interface{})
+		if vok && smok {
+			mergeMap(vm, sm, s)
+			return vm
+		}
+		return copyValue(src)
+	}
+	if !aok {
+		a = []interface{}{v}
+	}
+	if !sok {
+		sa = []interface{}{src}
+	}
+
+	switch s.mode {
+	case sliceReplace:
+		return copyValue(sa)
+	case sliceByIndex:
+		for i, sv := range sa {
+			if i < len(a) {
+				a[i] = mergeValue(a[i], sv, s)
+				continue
+			}
+			a = append(a, copyValue(sv))
+		}
+		return a
+	case sliceByKey:
+	members:
+		for _, sv := range sa {
+			if sm, ok := sv.(map[string]interface{}); ok {
+				if kv, ok := sm[s.key]; ok {
+					for i, vv := range a {
+						if vm, ok := vv.(map[string]interface{}); ok {
+							if mk, ok := vm[s.key]; ok && valuesEqual(mk, kv) {
+								a[i] = mergeValue(vm, sm, s)
+								continue members
+							}
+						}
+					}
+				}
+			}
+			a = append(a, copyValue(sv))
+		}
+		return a
+	}
+	for _, sv := range sa {
+		a = append(a, copyValue(sv))
+	}
+	return a
+}
@@ -0,0 +1,96 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	fmt.Println("\n------------ merge_test.go")
+	base := func() Map {
+		return Map{"config": map[string]interface{}{
+			"name": "base",
+			"log":  map[string]interface{}{"level": "info", "file": "a.log"},
+			"server": []interface{}{
+				map[string]interface{}{"-id": "a", "port": float64(80)},
+				map[string]interface{}{"-id": "b", "port": float64(81)},
+			},
+			"tag": "x",
+		}}
+	}
+	src := Map{"config": map[string]interface{}{
+		"name": "layer",
+		"log":  map[string]interface{}{"level": "debug"},
+		"server": []interface{}{
+			map[string]interface{}{"-id": "b", "port": 8081, "tls": "on"},
+			map[string]interface{}{"-id": "c", "port": float64(82)},
+		},
+		"tag":  []interface{}{"y"},
+		"cert": "c.pem",
+	}}
+
+	checks := []struct {
+		strategy []SliceStrategy
+		server   []interface{}
+		tag      []interface{}
+	}{
+		{nil,
+			[]interface{}{
+				map[string]interface{}{"-id": "a", "port": float64(80)},
+				map[string]interface{}{"-id": "b", "port": float64(81)},
+				map[string]interface{}{"-id": "b", "port": 8081, "tls": "on"},
+				map[string]interface{}{"-id": "c", "port": float64(82)},
+			},
+			[]interface{}{"x", "y"}},
+		{[]SliceStrategy{SliceReplace},
+			[]interface{}{
+				map[string]interface{}{"-id": "b", "port": 8081, "tls": "on"},
+				map[string]interface{}{"-id": "c", "port": float64(82)},
+			},
+			[]interface{}{"y"}},
+		{[]SliceStrategy{SliceByIndex},
+			[]interface{}{
+				map[string]interface{}{"-id": "b", "port": 8081, "tls": "on"},
+				map[string]interface{}{"-id": "c", "port": float64(82)},
+			},
+			[]interface{}{"y"}},
+		{[]SliceStrategy{SliceByKey("-id")},
+			[]interface{}{
+				map[string]interface{}{"-id": "a", "port": float64(80)},
+				map[string]interface{}{"-id": "b", "port": 8081, "tls": "on"},
+				map[string]interface{}{"-id": "c", "port": float64(82)},
+			},
+			[]interface{}{"x", "y"}},
+	}
+	for i, c := range checks {
+		m := base()
+		if err := m.Merge(src, c.strategy...); err != nil {
+			t.Fatal(err)
+		}
+		want := Map{"config": map[string]interface{}{
+			"name":   "layer",
+			"log":    map[string]interface{}{"level": "debug", "file": "a.log"},
+			"server": c.server,
+			"tag":    c.tag,
+			"cert":   "c.pem",
+		}}
+		if !Equal(m, want) {
+			t.Fatal(i, "got:", m, "want:", want)
+		}
+	}
+
+	// src values are copied
+	m := base()
+	if err := m.Merge(src); err != nil {
+		t.Fatal(err)
+	}
+	src["config"].(map[string]interface{})["server"].([]interface{})[0].(map[string]interface{})["tls"] = "off"
+	if v, _ := m.ValueForPath("config.server[2].tls"); v != "on" {
+		t.Fatal("config.server[2].tls:", v)
+	}
+
+	var nm Map
+	if err := nm.Merge(src); err == nil {
+		t.Fatal("no error for nil Map")
+	}
+}
@@ -58,7 +58,7 @@ func (mv Map) Elements(path string) ([]string, error) {
 // If the path is an element with attributes, return a list of the attribute
 // keys.  (The list is alphabeticly sorted.)  NOTE: Map keys that are not prefixed with
 // '-', a hyphen, are not treated as attributes; see m.Elements(path). Also, if the
-// attribute prefix is "" - SetAttrPrefix("") or PrependAttrWithHyphen(false) - then
+// attribute prefix is "" - PrependAttrWithHyphen(false) - then
 // there are no identifiable attributes.
 func (mv Map) Attributes(path string) ([]string, error) {
 	a, err := mv.ValueForPath(path)
@@ -84,3 +84,58 @@ func (mv Map) Attributes(path string) ([]string, error) {
 	}
 	return nil, fmt.Errorf("no attributes for path: %s", path)
 }
+
+// GetAttr returns the value of the attribute 'name' of the element at 'path'.
+// The attribute prefix is prepended to 'name' - see SetAttrPrefix(); attributes decoded
+// per DecodeAttrsAsMap() are also found.
+func (mv Map) GetAttr(path, name string) (interface{}, error) {
+	e, err := mv.ValueForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if ee, ok := e.(map[string]interface{}); ok {
+		if len(attrPrefix) > 0 {
+			if v, ok := ee[attrPrefix+name]; ok {
+				return v, nil
+			}
+		}
+		if aa, ok := ee["#attr"].(map[string]interface{}); ok {
+			if v, ok := aa[name]; ok {
+				return v, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no attribute %s for path: %s", name, path)
+}
+
+// SetAttr sets the attribute 'name' of the element at 'path' to 'value'. The attribute
+// prefix is prepended to 'name' - see SetAttrPrefix(); however, if the element has
+// attributes decoded per DecodeAttrsAsMap(), the attribute is set in the "#attr" map.
+// If the element is a simple element, its value becomes the "#text" value. An error
+// is returned if the attribute prefix is "", since there are no identifiable attributes.
+func (mv Map) SetAttr(path, name string, value interface{}) error {
+	if len(attrPrefix) == 0 {
+		return fmt.Errorf("no attribute prefix - cannot set attribute %s", name)
+	}
+	e, err := mv.ValueForPath(path)
+	if err != nil {
+		return err
+	}
+	switch e.(type) {
+	case map[string]interface{}:
+		ee := e.(map[string]interface{})
+		if aa, ok := ee["#attr"].(map[string]interface{}); ok {
+			aa[name] = value
+			return nil
+		}
+		ee[attrPrefix+name] = value
+		return nil
+	case []interface{}:
+		return fmt.Errorf("path is a list: %s", path)
+	}
+	ee := map[string]interface{}{attrPrefix + name: value}
+	if s, ok := e.(string); !ok || s != "" {
+		ee["#text"] = e
+	}
+	return mv.SetValueForPath(ee, path)
+}
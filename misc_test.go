@@ -266,3 +266,56 @@ func TestPreserveSpaceDisableByDefault(t *testing.T) {
 	// Set it back to false after all tests are done
 	DisableTrimWhiteSpace(false)
 }
+
+func TestGetSetAttr(t *testing.T) {
+	m, err := NewMapXml([]byte(`<doc><elem id="1"><sub>x</sub></elem><simple>text</simple><empty/></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := m.GetAttr("doc.elem", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "1" {
+		t.Fatal("doc.elem id:", v)
+	}
+	if _, err = m.GetAttr("doc.elem", "sub"); err == nil {
+		t.Fatal("subelement returned as attribute")
+	}
+
+	for _, path := range []string{"doc.elem", "doc.simple", "doc.empty"} {
+		if err = m.SetAttr(path, "seq", 2); err != nil {
+			t.Fatal(path, err)
+		}
+		if v, err = m.GetAttr(path, "seq"); err != nil || v.(int) != 2 {
+			t.Fatal(path, "seq:", v, err)
+		}
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc><elem id="1" seq="2"><sub>x</sub></elem><empty seq="2"/><simple seq="2">text</simple></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if err = m.SetAttr("doc.none", "seq", 2); err == nil {
+		t.Fatal("no error for bad path")
+	}
+
+	DecodeAttrsAsMap(true)
+	defer DecodeAttrsAsMap(false)
+	m, err = NewMapXml([]byte(`<doc type="a"><type>b</type></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.SetAttr("doc", "seq", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = m.ValueForPath("doc.#attr.seq"); err != nil || v.(string) != "1" {
+		t.Fatal("doc.#attr.seq:", v, err)
+	}
+	if v, err = m.GetAttr("doc", "type"); err != nil || v.(string) != "a" {
+		t.Fatal("doc type:", v, err)
+	}
+}
@@ -47,6 +47,33 @@ func (mv Map) Copy() (Map, error) {
 	return NewMapJson(j)
 }
 
+// With returns a copy of mv with the 'updates' applied - each is a path:value pair
+// set using SetValueForPath() - leaving mv unchanged. The updates are applied in path
+// order; on error the partially updated copy is not returned. Unlike mv.Copy(), the
+// values are copied without a JSON round trip, so their types are preserved; but, only
+// map[string]interface{} and []interface{} values are copied, other values are shared.
+func (mv Map) With(updates map[string]interface{}) (Map, error) {
+	c := copyValue(map[string]interface{}(mv)).(map[string]interface{})
+	paths := make([]string, 0, len(updates))
+	for p := range updates {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := Map(c).SetValueForPath(updates[p], p); err != nil {
+			return nil, fmt.Errorf("With: %s: %s", p, err.Error())
+		}
+	}
+	return c, nil
+}
+
+// Wrap returns a new Map with mv as the value for 'key' - {key: mv} - e.g., to put an
+// unwrapped payload back in an envelope before encoding it. The values of mv are not
+// copied, so changes to either Map are reflected in the other.
+func (mv Map) Wrap(key string) Map {
+	return Map{key: map[string]interface{}(mv)}
+}
+
 // --------------- StringIndent ... from x2j.WriteMap -------------
 
 // Pretty print a Map.
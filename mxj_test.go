@@ -44,3 +44,50 @@ func TestMap(t *testing.T) {
 	mm, _ := m.Copy()
 	fmt.Println("TestMap, m.Copy() -\n", mm)
 }
+
+func TestWith(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"a": "1", "list": []interface{}{map[string]interface{}{"b": 2}}}}
+	n, err := m.With(map[string]interface{}{"doc.a": "x", "doc.c": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := n.ValueForPath("doc.a"); v != "x" {
+		t.Fatal("doc.a:", v)
+	}
+	if v, _ := n.ValueForPath("doc.c"); v != 3 {
+		t.Fatal("doc.c:", v)
+	}
+	if v, _ := m.ValueForPath("doc.a"); v != "1" {
+		t.Fatal("original doc.a:", v)
+	}
+	if _, err := m.ValueForPath("doc.c"); err == nil {
+		t.Fatal("original has doc.c")
+	}
+	// the copy is deep
+	n["doc"].(map[string]interface{})["list"].([]interface{})[0].(map[string]interface{})["b"] = 4
+	if v, _ := m.ValueForPath("doc.list.b"); v != 2 {
+		t.Fatal("original doc.list.b:", v)
+	}
+
+	if _, err = m.With(map[string]interface{}{"doc.a.z": 1}); err == nil {
+		t.Fatal("no error for path through a simple value")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	m := Map{"id": "1", "body": "x"}
+	w := m.Wrap("envelope")
+	x, err := w.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<envelope><body>x</body><id>1</id></envelope>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if v, _ := w.ValueForPath("envelope.id"); v != "1" {
+		t.Fatal("envelope.id:", v)
+	}
+	if len(m) != 2 {
+		t.Fatal("Map modified:", m)
+	}
+}
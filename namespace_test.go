@@ -19,3 +19,40 @@ func TestBeautifyXml(t *testing.T) {
 	fmt.Println(flatxml)
 	fmt.Println(string(v))
 }
+
+func TestXmlnsRoundTrip(t *testing.T) {
+	PrependAttrWithHyphen(true)
+
+	// default name spaces and prefix declarations
+	data := []byte(`<doc xmlns="urn:d" xmlns:a="urn:a" xmlns:b="urn:b"><item id="1" xmlns="urn:i">text</item><list><x>1</x><x>2</x></list></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"doc.-xmlns": "urn:d", "doc.-xmlns:a": "urn:a", "doc.-xmlns:b": "urn:b", "doc.item.-xmlns": "urn:i"} {
+		if vv, _ := m.ValueForPath(k); vv != v {
+			t.Fatal(k, ":", vv)
+		}
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != string(data) {
+		t.Fatal("got:", string(x), "want:", string(data))
+	}
+
+	// prefixed names, with PreserveNamespacePrefixes
+	PreserveNamespacePrefixes(true)
+	defer PreserveNamespacePrefixes(false)
+	data = []byte(`<a:doc xmlns:a="urn:a" xmlns:b="urn:b"><a:item b:id="1">text</a:item><b:empty/></a:doc>`)
+	if m, err = NewMapXml(data); err != nil {
+		t.Fatal(err)
+	}
+	if x, err = m.Xml(); err != nil {
+		t.Fatal(err)
+	}
+	if string(x) != string(data) {
+		t.Fatal("got:", string(x), "want:", string(data))
+	}
+}
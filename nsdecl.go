@@ -0,0 +1,214 @@
+// nsdecl.go - encode a Map as XML with the name space declarations that its keys need.
+
+package mxj
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// XmlWithNamespaces encodes the Map as XML, as with mv.Xml(), adding the "xmlns:prefix"
+// declarations for the name space prefixes used in the element and attribute keys -
+// e.g., "soap:Body" or "-xl:href" - with the URIs in 'nsMap'. Each prefix is declared
+// once, on the deepest element that contains all the elements using it - so the
+// declarations are as local as possible without repetition. If an nsMap[""] URI is
+// provided, it is declared as the default name space, "xmlns", on the root element,
+// unless the root element declares it.
+// Prefixes that are declared by "xmlns:prefix" attributes in the Map are not declared
+// again for the elements in the scope of the declaration - the declaring element and
+// its descendants; the "xml" prefix is never declared. The Map is not modified.
+// Error is returned if a prefix that needs to be declared is not in 'nsMap'.
+//	NOTE: use PreserveNamespacePrefixes(true) to keep the prefixes in decoded Map keys.
+func (mv Map) XmlWithNamespaces(nsMap map[string]string, rootTag ...string) ([]byte, error) {
+	m, err := mv.withNamespaces(nsMap, rootTag...)
+	if err != nil {
+		return nil, err
+	}
+	return m.Xml()
+}
+
+// XmlIndentWithNamespaces is mv.XmlWithNamespaces() encoded as with mv.XmlIndent().
+func (mv Map) XmlIndentWithNamespaces(nsMap map[string]string, prefix, indent string, rootTag ...string) ([]byte, error) {
+	m, err := mv.withNamespaces(nsMap, rootTag...)
+	if err != nil {
+		return nil, err
+	}
+	return m.XmlIndent(prefix, indent)
+}
+
+// nsStep is the key and, for list members, index of an element beneath its parent.
+type nsStep struct {
+	key   string
+	index int // -1 if the value isn't a list
+}
+
+// withNamespaces returns a copy of mv, wrapped in its root element per 'rootTag', with
+// the name space declaration attributes added.
+func (mv Map) withNamespaces(nsMap map[string]string, rootTag ...string) (Map, error) {
+	c := copyValue(map[string]interface{}(mv)).(map[string]interface{})
+	top := c
+	if len(rootTag) == 1 {
+		top = map[string]interface{}{rootTag[0]: c}
+	} else if len(c) != 1 {
+		top = map[string]interface{}{DefaultRootTag: c}
+	}
+
+	uses := make(map[string][][]nsStep) // prefix:paths of the elements where it isn't declared
+	nsUses(top, nil, uses, nil)
+
+	prefixes := make([]string, 0, len(uses))
+	for p := range uses {
+		if p != "xml" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	sort.Strings(prefixes)
+	for _, p := range prefixes {
+		uri, ok := nsMap[p]
+		if !ok {
+			return nil, fmt.Errorf("XmlWithNamespaces: no name space URI for prefix: %s", p)
+		}
+		// the uses in each root element - there's one unless the root value is a list
+		byRoot := make(map[nsStep][][]nsStep)
+		var roots []nsStep
+		for _, u := range uses[p] {
+			if _, ok := byRoot[u[0]]; !ok {
+				roots = append(roots, u[0])
+			}
+			byRoot[u[0]] = append(byRoot[u[0]], u)
+		}
+		for _, r := range roots {
+			nsDeclare(top, nsCommonPath(byRoot[r]), "xmlns:"+p, uri)
+		}
+	}
+	if uri, ok := nsMap[""]; ok {
+		for k, v := range top {
+			if a, ok := v.([]interface{}); ok {
+				for i, vv := range a {
+					if !nsDeclares(elemScope(vv, nil), "") {
+						nsDeclare(top, []nsStep{{k, i}}, "xmlns", uri)
+					}
+				}
+				continue
+			}
+			if !nsDeclares(elemScope(v, nil), "") {
+				nsDeclare(top, []nsStep{{k, -1}}, "xmlns", uri)
+			}
+		}
+	}
+	return Map(top), nil
+}
+
+// nsUses records, for each name space prefix, the elements beneath 'm' that use it where
+// it isn't declared; 'path' is the path of the element with the value 'm' and 'scope' has
+// the declarations of the element and its ancestors - see nsScope.
+func nsUses(m map[string]interface{}, path []nsStep, uses map[string][][]nsStep, scope map[string]string) {
+	use := func(name string, path []nsStep, scope map[string]string) {
+		if prefix, _ := splitNSName(name); prefix != "" && !nsDeclares(scope, prefix) {
+			uses[prefix] = append(uses[prefix], path)
+		}
+	}
+	attr := func(name string) {
+		if name != "xmlns" && !strings.HasPrefix(name, "xmlns:") {
+			use(name, path, scope)
+		}
+	}
+	for k, v := range m {
+		switch {
+		case k == "#text":
+			continue
+		case k == "#attr":
+			if am, ok := v.(map[string]interface{}); ok {
+				for ak := range am {
+					attr(ak)
+				}
+				continue
+			}
+		case lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix):
+			attr(k[lenAttrPrefix:])
+			continue
+		}
+		elem := func(step nsStep, v interface{}) {
+			p := append(path[:len(path):len(path)], step)
+			s := elemScope(v, scope)
+			use(k, p, s)
+			if vm, ok := v.(map[string]interface{}); ok {
+				nsUses(vm, p, uses, s)
+			}
+		}
+		if a, ok := v.([]interface{}); ok {
+			for i, vv := range a {
+				elem(nsStep{k, i}, vv)
+			}
+			continue
+		}
+		elem(nsStep{k, -1}, v)
+	}
+}
+
+// elemScope returns 'scope' with the declarations of the element value 'v' - see nsScope.
+func elemScope(v interface{}, scope map[string]string) map[string]string {
+	if m, ok := v.(map[string]interface{}); ok {
+		return nsScope(m, scope)
+	}
+	return scope
+}
+
+// nsDeclares reports whether 'prefix' is declared in 'scope'.
+func nsDeclares(scope map[string]string, prefix string) bool {
+	_, ok := scope[prefix]
+	return ok
+}
+
+// nsCommonPath returns the longest common leading steps of 'paths'.
+func nsCommonPath(paths [][]nsStep) []nsStep {
+	c := paths[0]
+	for _, p := range paths[1:] {
+		n := 0
+		for n < len(c) && n < len(p) && c[n] == p[n] {
+			n++
+		}
+		c = c[:n]
+	}
+	return c
+}
+
+// nsDeclare adds the 'name'="uri" attribute to the element at 'path' beneath 'top'.
+func nsDeclare(top map[string]interface{}, path []nsStep, name, uri string) {
+	m := top
+	for i, s := range path {
+		v := m[s.key]
+		var a []interface{}
+		if s.index >= 0 {
+			a = v.([]interface{})
+			v = a[s.index]
+		}
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			// a simple element gets a "#text" key, as with attributes
+			vm = make(map[string]interface{})
+			if v != nil && v != "" {
+				vm["#text"] = v
+			}
+			if a != nil {
+				a[s.index] = vm
+			} else {
+				m[s.key] = vm
+			}
+		}
+		if i == len(path)-1 {
+			if lenAttrPrefix > 0 {
+				vm[attrPrefix+name] = uri
+			} else {
+				am, ok := vm["#attr"].(map[string]interface{})
+				if !ok {
+					am = make(map[string]interface{})
+					vm["#attr"] = am
+				}
+				am[name] = uri
+			}
+		}
+		m = vm
+	}
+}
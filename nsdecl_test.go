@@ -0,0 +1,76 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestXmlWithNamespaces(t *testing.T) {
+	fmt.Println("\n------------ nsdecl_test.go")
+	PrependAttrWithHyphen(true)
+
+	ns := map[string]string{"soap": "urn:soap", "m": "urn:m", "xl": "urn:xl", "": "urn:default"}
+	m := Map{"soap:Envelope": map[string]interface{}{
+		"soap:Body": map[string]interface{}{
+			"m:Price": []interface{}{
+				map[string]interface{}{"m:Amount": "5"},
+				map[string]interface{}{"m:Amount": "6"},
+			},
+			"link": map[string]interface{}{"-xl:href": "a.html"},
+			"note": "x",
+		},
+	}}
+	x, err := m.XmlWithNamespaces(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<soap:Envelope xmlns="urn:default" xmlns:soap="urn:soap"><soap:Body xmlns:m="urn:m">` +
+		`<link xl:href="a.html" xmlns:xl="urn:xl"/><m:Price><m:Amount>5</m:Amount></m:Price><m:Price><m:Amount>6</m:Amount></m:Price>` +
+		`<note>x</note></soap:Body></soap:Envelope>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "\nwant:", want)
+	}
+	if _, ok := m["soap:Envelope"].(map[string]interface{})["-xmlns:soap"]; ok {
+		t.Fatal("Map modified")
+	}
+
+	// a simple element, a rootTag and a prefix that's already declared
+	m = Map{"a:x": "1", "b:y": "2", "-xmlns:b": "urn:b"}
+	if x, err = m.XmlWithNamespaces(map[string]string{"a": "urn:a"}, "root"); err != nil {
+		t.Fatal(err)
+	}
+	if want = `<root xmlns:b="urn:b"><a:x xmlns:a="urn:a">1</a:x><b:y>2</b:y></root>`; string(x) != want {
+		t.Fatal("got:", string(x), "\nwant:", want)
+	}
+
+	// a declaration in a subtree doesn't apply to its siblings
+	m = Map{"doc": map[string]interface{}{
+		"one": map[string]interface{}{"-xmlns:p": "urn:p", "p:a": "1"},
+		"two": map[string]interface{}{"p:a": "2"},
+	}}
+	if x, err = m.XmlWithNamespaces(map[string]string{"p": "urn:p"}); err != nil {
+		t.Fatal(err)
+	}
+	if want = `<doc><one xmlns:p="urn:p"><p:a>1</p:a></one><two><p:a xmlns:p="urn:p">2</p:a></two></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "\nwant:", want)
+	}
+
+	if x, err = (Map{"a:x": "1"}).XmlWithNamespaces(nil); err == nil {
+		t.Fatal("no error for undeclared prefix:", string(x))
+	}
+
+	// round trip
+	PreserveNamespacePrefixes(true)
+	defer PreserveNamespacePrefixes(false)
+	data := []byte(`<p:doc xmlns:p="urn:p"><p:a>1</p:a></p:doc>`)
+	if m, err = NewMapXml(data); err != nil {
+		t.Fatal(err)
+	}
+	delete(m["p:doc"].(map[string]interface{}), "-xmlns:p")
+	if x, err = m.XmlIndentWithNamespaces(map[string]string{"p": "urn:p"}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if eq, err := XmlEqual(x, data); err != nil || !eq {
+		t.Fatal("got:", string(x), err)
+	}
+}
@@ -0,0 +1,78 @@
+// nsdrop.go - remove the name space prefixes and declarations from a Map.
+
+package mxj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DropNamespaces removes the name space prefixes from all the keys of the Map -
+// e.g., "soap:Envelope" becomes "Envelope" and "-xl:href" becomes "-href" - and deletes
+// all the name space declaration attributes, "-xmlns" and "-xmlns:prefix". It returns
+// the number of keys renamed or deleted. The keys of "#attr" maps - see
+// DecodeAttrsAsMap - are handled as attributes.
+// Error is returned, and the Map is not modified, if two keys of a map would have the
+// same name - e.g., "a:id" and "b:id".
+// The prefixes are only in the Map keys if PreserveNamespacePrefixes(true) is set.
+func (mv Map) DropNamespaces() (int, error) {
+	var r []rekeyOp
+	if err := dropNamespaces(map[string]interface{}(mv), "", false, &r); err != nil {
+		return 0, err
+	}
+	// as in mv.Rekey(); a rekeyOp with new == "" is a delete
+	for _, v := range r {
+		delete(v.m, v.old)
+	}
+	for _, v := range r {
+		if v.new != "" {
+			v.m[v.new] = v.val
+		}
+	}
+	return len(r), nil
+}
+
+// dropNamespaces adds the rekeyOp's for 'v' to 'r'; 'isAttrMap' is 'true' for "#attr" maps.
+func dropNamespaces(v interface{}, path string, isAttrMap bool, r *[]rekeyOp) error {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		names := make(map[string]string, len(m)) // new:old
+		for k, vv := range m {
+			var prefix string
+			name := k
+			if !isAttrMap && lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix) {
+				prefix, name = attrPrefix, k[lenAttrPrefix:]
+			}
+			// with no attribute prefix, "xmlns" keys are taken to be attributes
+			isAttr := isAttrMap || prefix != "" || lenAttrPrefix == 0
+			if isAttr && (name == "xmlns" || strings.HasPrefix(name, "xmlns:")) {
+				*r = append(*r, rekeyOp{m: m, old: k})
+				continue
+			}
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if err := dropNamespaces(vv, p, k == "#attr", r); err != nil {
+				return err
+			}
+			_, local := splitNSName(name)
+			nk := prefix + local
+			if old, ok := names[nk]; ok {
+				return fmt.Errorf("DropNamespaces: keys %q and %q would both be %q at path: %s", old, k, nk, path)
+			}
+			names[nk] = k
+			if nk != k {
+				*r = append(*r, rekeyOp{m: m, old: k, new: nk, val: vv})
+			}
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			if err := dropNamespaces(vv, path, false, r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDropNamespaces(t *testing.T) {
+	fmt.Println("\n------------ nsdrop_test.go")
+	PrependAttrWithHyphen(true)
+	PreserveNamespacePrefixes(true)
+	defer PreserveNamespacePrefixes(false)
+
+	data := []byte(`<soap:Envelope xmlns:soap="urn:soap" xmlns="urn:default"><soap:Body><m:Price xmlns:m="urn:m" m:currency="EUR" id="1"><m:Amount>5</m:Amount><m:Amount>6</m:Amount></m:Price></soap:Body></soap:Envelope>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := m.DropNamespaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 8 { // 3 declarations, 5 prefixed keys
+		t.Fatal("n:", n)
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<Envelope><Body><Price currency="EUR" id="1"><Amount>5</Amount><Amount>6</Amount></Price></Body></Envelope>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// collision
+	m = Map{"doc": map[string]interface{}{"a:id": "1", "b:id": "2", "-xmlns:a": "urn:a"}}
+	if _, err = m.DropNamespaces(); err == nil {
+		t.Fatal("no error for key collision")
+	}
+	if _, ok := m["doc"].(map[string]interface{})["-xmlns:a"]; !ok {
+		t.Fatal("Map modified on error:", m)
+	}
+
+	// "#attr" maps
+	m = Map{"doc": map[string]interface{}{"#attr": map[string]interface{}{"xmlns:a": "urn:a", "a:id": "1"}}}
+	if n, err = m.DropNamespaces(); err != nil || n != 2 {
+		t.Fatal("n:", n, "err:", err)
+	}
+	if v, _ := m.ValueForPath("doc.#attr.id"); v != "1" {
+		t.Fatal("got:", m)
+	}
+}
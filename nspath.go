@@ -0,0 +1,186 @@
+// nspath.go - query a Map by name space URI and local name.
+
+package mxj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// xmlNamespace is the URI that the "xml" prefix is bound to by definition.
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// nsKey is a parsed ValuesForPathNS path node.
+type nsKey struct {
+	space  string // bound URI
+	local  string
+	hasNS  bool // node has a prefix bound by 'nsMap'
+	isAttr bool
+}
+
+// ValuesForPathNS retrieves all values for a name space qualified path from the Map.
+// If len(returned_values) == 0, then no match. On error, the returned array is 'nil'.
+//   'path' is a dot-separated path of key values using "prefix:local" syntax.
+//          - The prefixes are those bound in 'nsMap', not those used in the Map keys; so
+//            a key matches a path node if they have the same local name and their prefixes
+//            are bound to the same URI.
+//          - The prefixes in the Map keys are resolved using the "xmlns" and "xmlns:prefix"
+//            attribute keys in scope; unprefixed element keys are in the default name space,
+//            unprefixed attribute keys are in no name space.
+//          - Path nodes without a prefix are matched by local name, only.
+//          - A path node of "*" or "prefix:*" matches any local name.
+//          - For attributes prefix the path node with the attribute prefix, e.g., "-xl:href";
+//            the attributes of "#attr" maps - see DecodeAttrsAsMap - follow a "#attr" path
+//            node, e.g., "a.#attr.xl:href".
+//   'nsMap' binds the path prefixes to name space URIs.
+// The Map keys must preserve the name space prefixes; see PreserveNamespacePrefixes.
+//	NOTE: indexed array references are not supported.
+func (mv Map) ValuesForPathNS(path string, nsMap map[string]string) ([]interface{}, error) {
+	keys, err := parseNSPath(path, nsMap)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]interface{}, 0, defaultArraySize)
+	valuesForPathNS(&ret, map[string]interface{}(mv), keys, map[string]string{"xml": xmlNamespace}, false)
+	return ret, nil
+}
+
+func parseNSPath(path string, nsMap map[string]string) ([]nsKey, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	nodes := strings.Split(path, ".")
+	keys := make([]nsKey, len(nodes))
+	for i, n := range nodes {
+		if strings.Contains(n, "[") {
+			return nil, fmt.Errorf("indexed array references not supported: %s", n)
+		}
+		if i > 0 && nodes[i-1] == "#attr" {
+			keys[i].isAttr = true
+		} else if lenAttrPrefix > 0 && strings.HasPrefix(n, attrPrefix) {
+			keys[i].isAttr = true
+			n = n[lenAttrPrefix:]
+		}
+		prefix, local := splitNSName(n)
+		keys[i].local = local
+		if prefix == "" {
+			continue
+		}
+		uri, ok := nsMap[prefix]
+		if !ok {
+			return nil, fmt.Errorf("no name space binding for prefix: %s", prefix)
+		}
+		keys[i].space = uri
+		keys[i].hasNS = true
+	}
+	return keys, nil
+}
+
+// splitNSName splits "prefix:local" into its parts.
+func splitNSName(s string) (string, string) {
+	if i := strings.Index(s, ":"); i > 0 {
+		return s[:i], s[i+1:]
+	}
+	return "", s
+}
+
+// nsScope returns 'scope' extended with the name space declarations in 'm', if any;
+// the declarations can be attribute keys or the keys of a "#attr" map.
+func nsScope(m map[string]interface{}, scope map[string]string) map[string]string {
+	s := scope
+	var copied bool
+	declare := func(k string, v interface{}) {
+		uri, ok := v.(string)
+		if !ok {
+			return
+		}
+		var prefix string
+		switch {
+		case k == "xmlns":
+		case strings.HasPrefix(k, "xmlns:"):
+			prefix = k[len("xmlns:"):]
+		default:
+			return
+		}
+		if !copied { // don't modify the ancestors' scope
+			s = make(map[string]string, len(scope)+1)
+			for kk, vv := range scope {
+				s[kk] = vv
+			}
+			copied = true
+		}
+		s[prefix] = uri
+	}
+	for k, v := range m {
+		if am, ok := v.(map[string]interface{}); ok && k == "#attr" {
+			for kk, vv := range am {
+				declare(kk, vv)
+			}
+			continue
+		}
+		if lenAttrPrefix > 0 && !strings.HasPrefix(k, attrPrefix) {
+			continue
+		}
+		declare(k[lenAttrPrefix:], v)
+	}
+	return s
+}
+
+// valuesForPathNS matches the keys of 'm' with keys[0]; 'isAttrMap' is 'true' if 'm'
+// is a "#attr" map, whose keys are all attributes.
+func valuesForPathNS(ret *[]interface{}, m map[string]interface{}, keys []nsKey, scope map[string]string, isAttrMap bool) {
+	for k, v := range m {
+		name := k
+		isAttr := isAttrMap
+		if !isAttrMap && lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix) {
+			name, isAttr = k[lenAttrPrefix:], true
+		}
+		if keys[0].isAttr != isAttr {
+			continue
+		}
+		prefix, local := splitNSName(name)
+		if keys[0].local != "*" && keys[0].local != local {
+			continue
+		}
+		if k == "#attr" && !isAttrMap {
+			// the element's declarations are in 'scope'
+			if vm, ok := v.(map[string]interface{}); ok && len(keys) > 1 {
+				valuesForPathNS(ret, vm, keys[1:], scope, true)
+				continue
+			}
+		}
+		switch v.(type) {
+		case []interface{}:
+			for _, vv := range v.([]interface{}) {
+				valueForPathNS(ret, prefix, isAttr, vv, keys, scope)
+			}
+		default:
+			valueForPathNS(ret, prefix, isAttr, v, keys, scope)
+		}
+	}
+}
+
+// valueForPathNS handles a single value whose key matches the local name of keys[0].
+func valueForPathNS(ret *[]interface{}, prefix string, isAttr bool, v interface{}, keys []nsKey, scope map[string]string) {
+	vm, isMap := v.(map[string]interface{})
+	if isMap {
+		// an element's declarations apply to its own name
+		scope = nsScope(vm, scope)
+	}
+	if keys[0].hasNS {
+		var uri string
+		if prefix != "" || !isAttr {
+			uri = scope[prefix]
+		}
+		if uri != keys[0].space {
+			return
+		}
+	}
+	if len(keys) == 1 {
+		*ret = append(*ret, v)
+		return
+	}
+	if isMap {
+		valuesForPathNS(ret, vm, keys[1:], scope, false)
+	}
+}
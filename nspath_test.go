@@ -0,0 +1,163 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValuesForPathNS(t *testing.T) {
+	fmt.Println("\n------------ nspath_test.go")
+	PreserveNamespacePrefixes(true)
+	defer PreserveNamespacePrefixes(false)
+
+	data := []byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+	<soap:Body xmlns="http://example.com/a">
+		<item>a1</item>
+		<b:item xmlns:b="http://example.com/b">b1</b:item>
+		<x:item xmlns:x="http://example.com/a" x:id="1" id="2">a2</x:item>
+	</soap:Body>
+</soap:Envelope>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nsMap := map[string]string{
+		"s": "http://schemas.xmlsoap.org/soap/envelope/",
+		"a": "http://example.com/a",
+		"b": "http://example.com/b",
+	}
+	v, err := m.ValuesForPathNS("s:Envelope.s:Body.a:item", nsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 2 {
+		t.Fatal("a:item, len:", len(v), v, "m:", m)
+	}
+	v, err = m.ValuesForPathNS("s:Envelope.s:Body.b:item", nsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 1 || v[0].(map[string]interface{})["#text"].(string) != "b1" {
+		t.Fatal("b:item:", v, "m:", m)
+	}
+	v, err = m.ValuesForPathNS("s:Envelope.s:Body.item", nsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 3 {
+		t.Fatal("item, len:", len(v), v, "m:", m)
+	}
+	v, err = m.ValuesForPathNS("s:Envelope.s:Body.a:item.-a:id", nsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 1 || v[0].(string) != "1" {
+		t.Fatal("-a:id:", v, "m:", m)
+	}
+	v, err = m.ValuesForPathNS("s:Envelope.b:Body", nsMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 0 {
+		t.Fatal("b:Body:", v)
+	}
+	if _, err = m.ValuesForPathNS("c:Envelope", nsMap); err == nil {
+		t.Fatal("no error for unbound prefix")
+	}
+}
+
+func TestPreserveNamespacePrefixes(t *testing.T) {
+	PreserveNamespacePrefixes(true)
+	defer PreserveNamespacePrefixes(false)
+
+	m, err := NewMapXml([]byte(`<ns:doc xmlns:ns="http://myns.com/ns"><ns:elem>x</ns:elem></ns:doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := m.ValueForPath("ns:doc.ns:elem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "x" {
+		t.Fatal("ns:doc.ns:elem:", v)
+	}
+	if _, err = m.ValueForPath("ns:doc.-xmlns:ns"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewMapXml([]byte(`<ns:doc><ns:elem>x</elem></ns:doc>`)); err == nil {
+		t.Fatal("no error for unterminated element")
+	}
+}
+
+func TestNamespacedAttributes(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	PreserveNamespacePrefixes(true)
+	defer PreserveNamespacePrefixes(false)
+
+	data := `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">` +
+		`<a xlink:href="#b" xml:lang="en"><svg:text xmlns:svg="http://www.w3.org/2000/svg">t</svg:text></a>` +
+		`<use x="1" xlink:href="#a"/></svg>`
+	nsMap := map[string]string{"xl": "http://www.w3.org/1999/xlink", "s": "http://www.w3.org/2000/svg"}
+	for _, asMap := range []bool{false, true} {
+		DecodeAttrsAsMap(asMap)
+		m, err := NewMapXml([]byte(data))
+		DecodeAttrsAsMap(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attr := "-"
+		if asMap {
+			attr = "#attr."
+		}
+
+		v, err := m.ValueForPath("svg.use." + attr + "xlink:href")
+		if err != nil || v != "#a" {
+			t.Fatal(asMap, "xlink:href:", v, err)
+		}
+		vals, err := m.ValuesForPathNS("s:svg.*."+attr+"xl:href", nsMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 2 {
+			t.Fatal(asMap, "xl:href:", vals)
+		}
+		if vals, _ = m.ValuesForPathNS("s:svg.a."+attr+"xml:lang", map[string]string{"xml": xmlNamespace, "s": nsMap["s"]}); len(vals) != 1 || vals[0] != "en" {
+			t.Fatal(asMap, "xml:lang:", vals)
+		}
+		if vals, _ = m.ValuesForPathNS("s:svg.a."+attr+"s:lang", nsMap); len(vals) != 0 {
+			t.Fatal(asMap, "s:lang:", vals)
+		}
+		if vals, _ = m.ValuesForPathNS("s:svg.a.s:text", nsMap); len(vals) != 1 {
+			t.Fatal(asMap, "s:text:", vals)
+		}
+
+		x, err := m.Xml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(x) != data {
+			t.Fatal(asMap, "got:", string(x), "want:", data)
+		}
+
+		if _, err = m.DropNamespaces(); err != nil {
+			t.Fatal(err)
+		}
+		if v, _ = m.ValueForPath("svg.a." + attr + "href"); v != "#b" {
+			t.Fatal(asMap, "href:", v)
+		}
+	}
+
+	// as for elements, the prefix is snake cased
+	CoerceKeysToSnakeCase(true)
+	m, err := NewMapXml([]byte(`<my-ns:el xmlns:my-ns="urn:x" my-ns:some-attr="1"/>`))
+	CoerceKeysToSnakeCase(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Map{"my_ns:el": map[string]interface{}{"-xmlns:my_ns": "urn:x", "-my_ns:some_attr": "1"}}
+	if !Equal(m, want) {
+		t.Fatal("got:", m, "want:", want)
+	}
+}
@@ -0,0 +1,100 @@
+// numbers.go - convert the numeric values of a Map to a single representation.
+
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// NormalizeNumbers converts all numeric values in the Map - float64, int, etc., and
+// json.Number values as well as strings that are valid JSON numbers, such as "5" or
+// "-1.5e3" - to float64 values, so that values decoded from XML and JSON compare equal.
+// It returns the number of values that were converted.
+// If 'asJsonNumber' is 'true', the values are converted to json.Number values instead;
+// integer text is kept as is - retaining large integers - and other values
+// are written as with strconv.FormatFloat(f, 'g', -1, 64), so "5", "5.0" and 5.0 are
+// all json.Number("5").
+//	NOTE: strings like "NaN", "+1", "0x10" or " 5" are not valid JSON numbers and are not converted.
+func (mv Map) NormalizeNumbers(asJsonNumber ...bool) int {
+	var jn bool
+	if len(asJsonNumber) == 1 {
+		jn = asJsonNumber[0]
+	}
+	var n int
+	for k, v := range mv {
+		mv[k] = normalizeNumbers(v, jn, &n)
+	}
+	return n
+}
+
+func normalizeNumbers(v interface{}, jn bool, n *int) interface{} {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		for k, vv := range m {
+			m[k] = normalizeNumbers(vv, jn, n)
+		}
+		return m
+	case []interface{}:
+		a := v.([]interface{})
+		for i, vv := range a {
+			a[i] = normalizeNumbers(vv, jn, n)
+		}
+		return a
+	case string:
+		s := v.(string)
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || !json.Valid([]byte(s)) {
+			return v
+		}
+		*n++
+		if jn {
+			return jsonNumber(s, f)
+		}
+		return f
+	case json.Number:
+		if jn {
+			s := string(v.(json.Number))
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return v
+			}
+			if nv := jsonNumber(s, f); nv != v {
+				*n++
+				return nv
+			}
+			return v
+		}
+	case float64:
+		if !jn {
+			return v
+		}
+		*n++
+		return jsonNumber("", v.(float64))
+	}
+	f, ok := numberValue(v)
+	if !ok {
+		return v
+	}
+	*n++
+	if jn {
+		if _, ok := v.(float32); ok {
+			return jsonNumber("", f)
+		}
+		return json.Number(fmt.Sprintf("%d", v)) // an integer type
+	}
+	return f
+}
+
+// jsonNumber returns the canonical json.Number value for the number 'f' with text 's'.
+func jsonNumber(s string, f float64) json.Number {
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(s)
+	}
+	if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return json.Number(s)
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+}
@@ -0,0 +1,62 @@
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestNormalizeNumbers(t *testing.T) {
+	fmt.Println("\n------------ numbers_test.go")
+	newMap := func() Map {
+		return Map{"doc": map[string]interface{}{
+			"a":    "5",
+			"b":    5.0,
+			"c":    []interface{}{"-1.5e3", int64(7), json.Number("2.50")},
+			"big":  "12345678901234567890",
+			"text": "five",
+			"nan":  "NaN",
+			"plus": "+1",
+			"ok":   true,
+		}}
+	}
+
+	m := newMap()
+	if n := m.NormalizeNumbers(); n != 5 {
+		t.Fatal("n:", n)
+	}
+	want := Map{"doc": map[string]interface{}{
+		"a":    float64(5),
+		"b":    float64(5),
+		"c":    []interface{}{float64(-1500), float64(7), float64(2.5)},
+		"big":  float64(12345678901234567890),
+		"text": "five",
+		"nan":  "NaN",
+		"plus": "+1",
+		"ok":   true,
+	}}
+	if !EqualExcept(m, want) {
+		t.Fatal("got:", m, "want:", want)
+	}
+	if a, _ := m.ValueForPath("doc.a"); a != float64(5) {
+		t.Fatalf("doc.a: %T", a)
+	}
+
+	m = newMap()
+	if n := m.NormalizeNumbers(true); n != 6 {
+		t.Fatal("n:", n)
+	}
+	for path, want := range map[string]interface{}{
+		"doc.a":    json.Number("5"),
+		"doc.b":    json.Number("5"),
+		"doc.c[0]": json.Number("-1500"),
+		"doc.c[1]": json.Number("7"),
+		"doc.c[2]": json.Number("2.5"),
+		"doc.big":  json.Number("12345678901234567890"),
+		"doc.text": "five",
+	} {
+		if v, _ := m.ValueForPath(path); v != want {
+			t.Fatalf("%s: got: %T %v want: %v", path, v, v, want)
+		}
+	}
+}
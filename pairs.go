@@ -0,0 +1,56 @@
+// pairs.go - convert a list of key/value pair elements into a map.
+
+package mxj
+
+import "fmt"
+
+// PairsToMap returns a map built from the list of key/value pair elements at 'path'.
+// For each element the map key is the string value - per "%v" formatting - of its
+// 'keyAttr' attribute and the map value is the value of its 'valueAttr' attribute:
+//	<props><prop key="a" value="1"/><prop key="b" value="2"/></props>
+// with mv.PairsToMap("props.prop", "key", "value") returns:
+//	map["a":"1", "b":"2"]
+// The attribute prefix is prepended to 'keyAttr' and 'valueAttr' - see SetAttrPrefix();
+// attributes decoded per DecodeAttrsAsMap() and subelements with those labels are also used.
+// If a key occurs more than once, the last value is kept.
+// Error is returned if an element is not a map[string]interface{} value or is missing
+// the key or value.
+func (mv Map) PairsToMap(path, keyAttr, valueAttr string) (map[string]interface{}, error) {
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make(map[string]interface{}, len(vals))
+	for i, v := range vals {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("PairsToMap: value #%d for path %s is not a map: %T", i, path, v)
+		}
+		k, ok := pairValue(m, keyAttr)
+		if !ok {
+			return nil, fmt.Errorf("PairsToMap: value #%d for path %s has no key %s", i, path, keyAttr)
+		}
+		val, ok := pairValue(m, valueAttr)
+		if !ok {
+			return nil, fmt.Errorf("PairsToMap: value #%d for path %s has no value %s", i, path, valueAttr)
+		}
+		pairs[fmt.Sprintf("%v", k)] = val
+	}
+	return pairs, nil
+}
+
+// pairValue returns the value of the 'name' attribute or subelement of 'm'.
+func pairValue(m map[string]interface{}, name string) (interface{}, bool) {
+	if len(attrPrefix) > 0 {
+		if v, ok := m[attrPrefix+name]; ok {
+			return v, true
+		}
+	}
+	if aa, ok := m["#attr"].(map[string]interface{}); ok {
+		if v, ok := aa[name]; ok {
+			return v, true
+		}
+	}
+	v, ok := m[name]
+	return v, ok
+}
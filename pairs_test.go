@@ -0,0 +1,31 @@
+package mxj
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPairsToMap(t *testing.T) {
+	fmt.Println("\n------------ pairs_test.go")
+	m, err := NewMapXml([]byte(`<props><prop key="a" value="1"/><prop key="b" value="2"/><prop key="c"><value>3</value></prop></props>`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := m.PairsToMap("props.prop", "key", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": float64(1), "b": float64(2), "c": float64(3)}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatal("got:", p, "want:", want)
+	}
+
+	if _, err = m.PairsToMap("props.prop", "name", "value"); err == nil {
+		t.Fatal("no error for missing key")
+	}
+	m, _ = NewMapXml([]byte(`<props><prop>a</prop><prop>b</prop></props>`))
+	if _, err = m.PairsToMap("props.prop", "key", "value"); err == nil {
+		t.Fatal("no error for simple elements")
+	}
+}
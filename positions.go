@@ -0,0 +1,329 @@
+// positions.go - annotate decoded elements with their position and bytes in the XML source.
+
+package mxj
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// decode with source positions - see DecodeSourcePositions.
+var decodeSourcePositions bool
+
+// decode with the source bytes of elements - see DecodeSourceBytes.
+var decodeSourceBytes bool
+
+// SourceLineKey and SourceColKey are the keys of the element positions recorded
+// per DecodeSourcePositions; SourceBytesKey is the key of the element source bytes
+// recorded per DecodeSourceBytes.
+var (
+	SourceLineKey  = "#line"
+	SourceColKey   = "#col"
+	SourceBytesKey = "#raw"
+)
+
+// DecodeSourcePositions causes NewMapXml(), NewMapXmlReader(), etc., to record the
+// source position of each element's start tag as SourceLineKey:N and SourceColKey:N
+// key:value pairs, with int values, in the element's map. Lines and columns are
+// numbered from 1; columns count bytes. As with IncludeTagSeqNum, simple element
+// values become maps - e.g., <b>text</b> on line 3 decodes as
+//	"b":{"#text":"text", "#line":3, "#col":5}
+// and an empty element as {"#line":3, "#col":5}. The path and key functions -
+// mv.ValuesForPath(), mv.ValueForPath(), mv.ValuesForKey(), mv.DeepGet(), etc. - step
+// over the annotations of simple elements: mv.ValueForPath("doc.b") returns "text",
+// while mv.ValueForPath("doc.b.#line") returns 3. Use mv.RemoveSourcePositions() to
+// drop the annotations - e.g., before encoding the Map - or see XmlEmitSourceComments.
+// If called with no argument, the setting is toggled.
+//	NOTE: not applicable to NewMapXmlSeq... functions.
+func DecodeSourcePositions(b ...bool) {
+	if len(b) == 0 {
+		decodeSourcePositions = !decodeSourcePositions
+	} else if len(b) == 1 {
+		decodeSourcePositions = b[0]
+	}
+}
+
+// DecodeSourceBytes causes NewMapXml(), NewMapXmlReader(), etc., to record the source
+// bytes of each element - from the start of the start tag to the end of the end tag - as
+// a SourceBytesKey:[]byte key:value pair in the element's map. As with
+// DecodeSourcePositions, simple element values become maps. Use mv.RawForPath() to get
+// the bytes of an element - e.g., to verify a digitally signed fragment of a document
+// without re-encoding it - and mv.RemoveSourcePositions() to drop the annotations.
+// The source bytes are as read by the xml.Decoder; for documents that are not UTF-8
+// encoded and are decoded with XmlCharsetReader, etc., they are the UTF-8 bytes returned
+// by the CharsetReader, not the document bytes. Name space declarations of enclosing
+// elements are not part of an element's bytes.
+// If called with no argument, the setting is toggled.
+//	NOTE: not applicable to NewMapXmlSeq... functions.
+func DecodeSourceBytes(b ...bool) {
+	if len(b) == 0 {
+		decodeSourceBytes = !decodeSourceBytes
+	} else if len(b) == 1 {
+		decodeSourceBytes = b[0]
+	}
+}
+
+// xmlPositions wraps the decoder's io.Reader and records the newline offsets, so
+// that xml.Decoder.InputOffset() values can be translated to line and column, and,
+// per DecodeSourceBytes, the bytes read.
+type xmlPositions struct {
+	r         io.Reader
+	n         int64   // bytes read
+	lines     []int64 // the offset of the first byte of each line after the first
+	positions bool    // record the line and column - see DecodeSourcePositions
+	record    bool    // record the bytes read in 'src' - see DecodeSourceBytes
+	src       []byte
+	converted bool // the xml.Decoder reads the CharsetReader output - see charsetReader
+}
+
+// sourcePositions returns 'r' wrapped per DecodeSourcePositions and DecodeSourceBytes;
+// if both settings are off 'r' is returned unchanged with a nil *xmlPositions.
+func sourcePositions(r io.Reader) (io.Reader, *xmlPositions) {
+	if !decodeSourcePositions && !decodeSourceBytes {
+		return r, nil
+	}
+	x := &xmlPositions{r: r, positions: decodeSourcePositions, record: decodeSourceBytes}
+	return x, x
+}
+
+func (x *xmlPositions) Read(b []byte) (int, error) {
+	n, err := x.r.Read(b)
+	if !x.converted {
+		x.add(b[:n])
+	}
+	return n, err
+}
+
+// add records the bytes 'b' read by the xml.Decoder.
+func (x *xmlPositions) add(b []byte) {
+	for i, c := range b {
+		if c == '\n' {
+			x.lines = append(x.lines, x.n+int64(i)+1)
+		}
+	}
+	if x.record {
+		x.src = append(x.src, b...)
+	}
+	x.n += int64(len(b))
+}
+
+// ReadByte keeps the io.ByteReader behavior of the wrapped reader - see NewMapXmlReader.
+func (x *xmlPositions) ReadByte() (byte, error) {
+	var c byte
+	var err error
+	if br, ok := x.r.(io.ByteReader); ok {
+		c, err = br.ReadByte()
+	} else {
+		b := make([]byte, 1)
+		var n int
+		if n, err = x.r.Read(b); n == 1 {
+			c, err = b[0], nil
+		} else if err == nil {
+			err = io.ErrNoProgress
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !x.converted {
+		x.add([]byte{c})
+	}
+	return c, nil
+}
+
+// charsetReader wraps the CharsetReader of 'p', if any, so that, once the xml.Decoder
+// reads the converted input, the converted bytes are recorded - xml.Decoder.InputOffset()
+// counts the bytes it reads, which are not the document bytes.
+func (x *xmlPositions) charsetReader(p *xml.Decoder) {
+	cr := p.CharsetReader
+	if cr == nil {
+		return
+	}
+	p.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		x.converted = true
+		r, err := cr(charset, input)
+		if err != nil {
+			return nil, err
+		}
+		return &convertedReader{r: r, x: x}, nil
+	}
+}
+
+// convertedReader records the bytes read from a CharsetReader - see charsetReader.
+type convertedReader struct {
+	r io.Reader
+	x *xmlPositions
+}
+
+func (c *convertedReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.x.add(b[:n])
+	return n, err
+}
+
+// lineCol translates the input offset 'off' to line and column.
+func (x *xmlPositions) lineCol(off int64) (int, int) {
+	i := sort.Search(len(x.lines), func(i int) bool { return x.lines[i] > off })
+	var start int64
+	if i > 0 {
+		start = x.lines[i-1]
+	}
+	return i + 1, int(off-start) + 1
+}
+
+// annotate returns the element value 'v' with the position of the start tag at 'off'
+// and the source bytes of the element, which end at 'end'.
+func (x *xmlPositions) annotate(v interface{}, off, end int64) interface{} {
+	var m map[string]interface{}
+	switch v.(type) {
+	case map[string]interface{}:
+		m = v.(map[string]interface{})
+	case string:
+		m = make(map[string]interface{})
+		if v.(string) != "" {
+			m["#text"] = v
+		}
+	default: // a cast simple element value
+		m = map[string]interface{}{"#text": v}
+	}
+	if x.positions {
+		m[SourceLineKey], m[SourceColKey] = x.lineCol(off)
+	}
+	if x.record && 0 <= off && off <= end && end <= int64(len(x.src)) {
+		// a full slice expression, so appending to the value can't modify 'src'
+		m[SourceBytesKey] = x.src[off:end:end]
+	}
+	return m
+}
+
+// RawForPath returns the source bytes of the element at 'path' recorded per
+// DecodeSourceBytes - e.g., "doc.signed" or "doc.item[1]". As with mv.UniqueValueForPath(),
+// PathNotExistError or PathNotUniqueError is returned if 'path' does not match exactly
+// one element - e.g., if it is the path of a list; error is also returned if there are
+// no recorded bytes for the element.
+func (mv Map) RawForPath(path string) ([]byte, error) {
+	// as mv.UniqueValueForPath(), with the annotations of simple elements
+	vals, err := mv.valuesForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	switch len(vals) {
+	case 0:
+		return nil, PathNotExistError
+	case 1:
+	default:
+		return nil, PathNotUniqueError
+	}
+	v := vals[0]
+	if m, ok := v.(map[string]interface{}); ok {
+		if b, ok := m[SourceBytesKey].([]byte); ok {
+			return append([]byte(nil), b...), nil
+		}
+	}
+	return nil, fmt.Errorf("RawForPath: no source bytes for path: %s", path)
+}
+
+// XmlEmitSourceComments causes mv.Xml(), mv.XmlIndent(), etc., to encode the SourceLineKey
+// value recorded per DecodeSourcePositions as a comment before the element - e.g.,
+// "<!-- line 3 -->" - so that transformed XML can be traced back to the source; the
+// SourceLineKey, SourceColKey and SourceBytesKey values are not encoded. Elements without
+// a SourceLineKey value are encoded as usual. (Not applicable to MapSeq values.)
+var XmlEmitSourceComments bool
+
+// withoutSourcePositions returns the element value 'm' without the annotations recorded
+// per DecodeSourcePositions and DecodeSourceBytes; 'm' is not modified.
+func withoutSourcePositions(m map[string]interface{}) interface{} {
+	n := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch k {
+		case SourceLineKey, SourceColKey, SourceBytesKey:
+			continue
+		}
+		n[k] = v
+	}
+	// a simple element value
+	switch len(n) {
+	case 0:
+		return ""
+	case 1:
+		if t, ok := n["#text"]; ok {
+			return t
+		}
+	}
+	return n
+}
+
+// RemoveSourcePositions removes the SourceLineKey and SourceColKey values recorded per
+// DecodeSourcePositions and the SourceBytesKey values recorded per DecodeSourceBytes,
+// restoring the Map as it is decoded without the annotations, and returns the number
+// of elements that were annotated.
+func (mv Map) RemoveSourcePositions() int {
+	var n int
+	for k, v := range mv {
+		mv[k] = removeSourcePositions(v, &n)
+	}
+	return n
+}
+
+func removeSourcePositions(v interface{}, n *int) interface{} {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		_, line := m[SourceLineKey]
+		_, col := m[SourceColKey]
+		_, src := m[SourceBytesKey]
+		if line || col || src {
+			delete(m, SourceLineKey)
+			delete(m, SourceColKey)
+			delete(m, SourceBytesKey)
+			*n++
+			// a simple element value
+			switch len(m) {
+			case 0:
+				return ""
+			case 1:
+				if t, ok := m["#text"]; ok && !decodeSimpleValuesAsMap {
+					return t
+				}
+			}
+		}
+		for k, vv := range m {
+			m[k] = removeSourcePositions(vv, n)
+		}
+		return m
+	case []interface{}:
+		a := v.([]interface{})
+		for i, vv := range a {
+			a[i] = removeSourcePositions(vv, n)
+		}
+	}
+	return v
+}
+
+// simpleSourceValue returns the text value of 'v' if it is a simple element value that is
+// a map only for its source annotations - see DecodeSourcePositions; otherwise, 'v'.
+func simpleSourceValue(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 || len(m) > 4 {
+		return v
+	}
+	var annotated bool
+	for k := range m {
+		switch k {
+		case SourceLineKey, SourceColKey, SourceBytesKey:
+			annotated = true
+		case "#text":
+		default:
+			return v
+		}
+	}
+	if !annotated {
+		return v
+	}
+	if t, ok := m["#text"]; ok {
+		return t
+	}
+	return "" // an empty element
+}
@@ -0,0 +1,222 @@
+package mxj
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSourcePositions(t *testing.T) {
+	fmt.Println("\n------------ positions_test.go")
+	PrependAttrWithHyphen(true)
+	DecodeSourcePositions(true)
+	defer DecodeSourcePositions(false)
+
+	data := []byte("<?xml version=\"1.0\"?>\n<doc seq=\"1\">\n  <a>text</a>\n  <b/><b>2</b>\n</doc>")
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		path      string
+		line, col int
+	}{
+		{"doc", 2, 1},
+		{"doc.a", 3, 3},
+		{"doc.b[0]", 4, 3},
+		{"doc.b[1]", 4, 7},
+	}
+	for _, c := range checks {
+		line, _ := m.ValueForPath(c.path + "." + SourceLineKey)
+		col, _ := m.ValueForPath(c.path + "." + SourceColKey)
+		if line != c.line || col != c.col {
+			t.Fatal(c.path, "got:", line, col, "want:", c.line, c.col)
+		}
+	}
+	if v, _ := m.ValueForPath("doc.a.#text"); v != "text" {
+		t.Fatal("doc.a.#text:", v)
+	}
+
+	// the path and key functions step over the annotations of simple elements
+	if v, _ := m.ValueForPath("doc.a"); v != "text" {
+		t.Fatal("doc.a:", v)
+	}
+	if v, _ := m.ValuesForPath("doc.b"); fmt.Sprint(v) != "[ 2]" {
+		t.Fatal("doc.b:", v)
+	}
+	if v, _ := m.ValuesForKey("a"); len(v) != 1 || v[0] != "text" {
+		t.Fatal("ValuesForKey a:", v)
+	}
+	if v, _ := m.DeepGet("doc.b[1]"); v != "2" {
+		t.Fatal("DeepGet doc.b[1]:", v)
+	}
+	mb, err := NewMapXml([]byte("<doc>\n  <b>text</b>\n</doc>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := mb.ValueForPath("doc.b"); v != "text" {
+		t.Fatal("doc.b:", v)
+	}
+	if v, _ := mb.ValueForPath("doc.b.#line"); v != 2 {
+		t.Fatal("doc.b.#line:", v)
+	}
+
+	// same positions from an io.Reader
+	mr, err := NewMapXmlReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !EqualExcept(m, mr) {
+		t.Fatal("reader got:", mr, "want:", m)
+	}
+
+	if n := m.RemoveSourcePositions(); n != 4 {
+		t.Fatal("removed:", n)
+	}
+	DecodeSourcePositions(false)
+	want, _ := NewMapXml(data)
+	if !EqualExcept(m, want) {
+		t.Fatal("got:", m, "want:", want)
+	}
+}
+
+func TestDecodeSourceBytes(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	DecodeSourceBytes(true)
+	defer DecodeSourceBytes(false)
+
+	signed := "<signed id=\"s1\">\n    <amount currency=\"EUR\">10.50</amount><!-- ok -->\n  </signed>"
+	data := []byte("<doc>\n  " + signed + "\n  <item>a</item><item/>\n</doc>")
+	for _, reader := range []bool{false, true} {
+		var m Map
+		var err error
+		if reader {
+			m, err = NewMapXmlReader(bytes.NewReader(data))
+		} else {
+			m, err = NewMapXml(data, true)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		checks := map[string]string{
+			"doc":               string(data),
+			"doc.signed":        signed,
+			"doc.signed.amount": `<amount currency="EUR">10.50</amount>`,
+			"doc.item[0]":       "<item>a</item>",
+			"doc.item[1]":       "<item/>",
+		}
+		for path, want := range checks {
+			b, err := m.RawForPath(path)
+			if err != nil {
+				t.Fatal(path, err)
+			}
+			if string(b) != want {
+				t.Fatalf("%s got: %q want: %q", path, b, want)
+			}
+		}
+		if _, err = m.RawForPath("doc.item"); err != PathNotUniqueError {
+			t.Fatal("list err:", err)
+		}
+		if _, err = m.RawForPath("doc.none"); err == nil {
+			t.Fatal("no error for missing path")
+		}
+		if _, err = m.RawForPath("doc.signed.-id"); err == nil {
+			t.Fatal("no error for attribute")
+		}
+
+		if n := m.RemoveSourcePositions(); n != 5 {
+			t.Fatal("removed:", n)
+		}
+		if v, _ := m.ValueForPath("doc.item[0]"); v != "a" {
+			t.Fatal("doc.item[0]:", v)
+		}
+		if _, err = m.RawForPath("doc"); err == nil {
+			t.Fatal("no error for removed source bytes")
+		}
+	}
+}
+
+// latin1Reader is an ISO-8859-1 CharsetReader.
+func latin1Reader(charset string, input io.Reader) (io.Reader, error) {
+	b, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return strings.NewReader(string(r)), nil
+}
+
+func TestDecodeSourceBytesCharset(t *testing.T) {
+	DecodeSourceBytes(true)
+	DecodeSourcePositions(true)
+	defer DecodeSourceBytes(false)
+	defer DecodeSourcePositions(false)
+	cr := XmlCharsetReader
+	XmlCharsetReader = latin1Reader
+	defer func() { XmlCharsetReader = cr }()
+
+	data := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<doc><a>caf\xe9 cr\xe8me</a>\n<b>\xe0</b></doc>")
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]string{
+		"doc":   "<doc><a>café crème</a>\n<b>à</b></doc>",
+		"doc.a": "<a>café crème</a>",
+		"doc.b": "<b>à</b>",
+	}
+	for path, want := range checks {
+		b, err := m.RawForPath(path)
+		if err != nil {
+			t.Fatal(path, err)
+		}
+		if string(b) != want {
+			t.Fatalf("%s got: %q want: %q", path, b, want)
+		}
+	}
+	if v, _ := m.ValueForPath("doc.b.#line"); v != 3 {
+		t.Fatal("doc.b.#line:", v)
+	}
+}
+
+func TestXmlEmitSourceComments(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	DecodeSourcePositions(true)
+	data := []byte("<doc seq=\"1\">\n  <a>text</a>\n  <b/><b>2</b>\n</doc>")
+	m, err := NewMapXml(data)
+	DecodeSourcePositions(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetValueForPath("x", "doc.c")
+
+	XmlEmitSourceComments = true
+	defer func() { XmlEmitSourceComments = false }()
+	x, err := m.XmlIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<!-- line 1 -->\n<doc seq=\"1\">\n  <!-- line 2 -->\n  <a>text</a>\n" +
+		"  <!-- line 3 -->\n  <b/>\n  <!-- line 3 -->\n  <b>2</b>\n  <c>x</c>\n</doc>"
+	if string(x) != want {
+		t.Fatalf("got: %q want: %q", x, want)
+	}
+
+	if x, err = m.Xml(); err != nil {
+		t.Fatal(err)
+	}
+	want = `<!-- line 1 --><doc seq="1"><!-- line 2 --><a>text</a>` +
+		`<!-- line 3 --><b/><!-- line 3 --><b>2</b><c>x</c></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if _, err = m.ValueForPath("doc.a." + SourceLineKey); err != nil {
+		t.Fatal("Map modified:", m)
+	}
+}
@@ -0,0 +1,135 @@
+// promote.go - use a subelement as the value of its parent element.
+
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PromoteChildAsValue replaces the element at 'path' with the value of its 'childKey'
+// subelement; the other subelements become attributes of the element. Thus
+//	<price><value>100</value><currency>USD</currency></price>
+// with mv.PromoteChildAsValue("price", "value") becomes the equivalent of
+//	<price currency="USD">100</price>
+// - map["price"]map["#text":"100", "-currency":"USD"]. Existing attributes are kept;
+// if there are no attributes, the element value is just the value of 'childKey'.
+// If the element has a "#attr" map value - see DecodeAttrsAsMap() - the subelements
+// are added to it rather than being keyed with the attribute prefix.
+// The 'path' may have wildcard, "*", nodes; all members of lists are handled, but
+// indexed list references are not supported.
+// Error is returned, and the Map is not modified, if 'path' does not exist, if an element
+// has no 'childKey' subelement, if it or a sibling is not a simple value - string, number
+// or boolean - or if the element has text - a "#text" key - as well as subelements.
+func (mv Map) PromoteChildAsValue(path, childKey string) error {
+	if strings.Contains(path, "[") {
+		return fmt.Errorf("PromoteChildAsValue: indexed list references not supported: %s", path)
+	}
+	// check every element first, so the Map is not modified on error
+	keys := strings.Split(path, ".")
+	n, err := promoteChild(map[string]interface{}(mv), keys, childKey, false)
+	if err == nil && n > 0 {
+		_, err = promoteChild(map[string]interface{}(mv), keys, childKey, true)
+	}
+	if err != nil {
+		return fmt.Errorf("PromoteChildAsValue: %s: %s", path, err.Error())
+	}
+	if n == 0 {
+		return fmt.Errorf("PromoteChildAsValue: path not found: %s", path)
+	}
+	return nil
+}
+
+// promoteChild handles the values for 'keys' in 'm' and returns the number of elements handled;
+// if 'apply' is 'false', the elements are only checked.
+func promoteChild(m map[string]interface{}, keys []string, child string, apply bool) (int, error) {
+	var cnt int
+	for k, v := range m {
+		if keys[0] != "*" && keys[0] != k {
+			continue
+		}
+		if list, ok := v.([]interface{}); ok {
+			for i, lv := range list {
+				n, nv, err := promoteChildValue(lv, keys, child, apply)
+				if err != nil {
+					return cnt, err
+				}
+				list[i] = nv
+				cnt += n
+			}
+			continue
+		}
+		n, nv, err := promoteChildValue(v, keys, child, apply)
+		if err != nil {
+			return cnt, err
+		}
+		m[k] = nv
+		cnt += n
+	}
+	return cnt, nil
+}
+
+// promoteChildValue returns the value that replaces 'v', the value for keys[0]; if 'apply'
+// is 'false', 'v' is only checked and is returned.
+func promoteChildValue(v interface{}, keys []string, child string, apply bool) (int, interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		if len(keys) == 1 {
+			return 0, v, fmt.Errorf("element is not a map: %T", v)
+		}
+		return 0, v, nil
+	}
+	if len(keys) > 1 {
+		n, err := promoteChild(m, keys[1:], child, apply)
+		return n, v, err
+	}
+
+	cv, ok := m[child]
+	if !ok {
+		return 0, v, fmt.Errorf("no child: %s", child)
+	}
+	if !isSimpleValue(cv) {
+		return 0, v, fmt.Errorf("child %s is not a simple value: %T", child, cv)
+	}
+	if _, ok := m["#text"]; ok {
+		return 0, v, fmt.Errorf("element has text, a #text key, and child: %s", child)
+	}
+	attrs, _ := m["#attr"].(map[string]interface{})
+	for k, sv := range m {
+		if k != child && !isSimpleValue(sv) && !(attrs != nil && k == "#attr") {
+			return 0, v, fmt.Errorf("sibling %s is not a simple value: %T", k, sv)
+		}
+	}
+	if !apply {
+		return 1, v, nil
+	}
+	delete(m, child)
+	for k, sv := range m {
+		switch {
+		case k == "#attr" && attrs != nil:
+		case lenAttrPrefix == 0 || strings.HasPrefix(k, attrPrefix):
+			// already an attribute, or can't be distinguished from one
+		case attrs != nil:
+			attrs[k] = sv
+			delete(m, k)
+		default:
+			m[attrPrefix+k] = sv
+			delete(m, k)
+		}
+	}
+	if len(m) == 0 {
+		return 1, cv, nil
+	}
+	m["#text"] = cv
+	return 1, m, nil
+}
+
+// isSimpleValue reports whether 'v' can be encoded as an attribute value.
+func isSimpleValue(v interface{}) bool {
+	switch v.(type) {
+	case string, []byte, float64, bool, int, int32, int64, float32, json.Number:
+		return true
+	}
+	return false
+}
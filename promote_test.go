@@ -0,0 +1,78 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPromoteChildAsValue(t *testing.T) {
+	fmt.Println("\n------------ promote_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc><price id="1"><value>100</value><currency>USD</currency></price><price><value>5</value></price></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.PromoteChildAsValue("doc.price", "value"); err != nil {
+		t.Fatal(err)
+	}
+	v, _ := m.ValueForPath("doc.price[0]")
+	if fmt.Sprint(v) != "map[#text:100 -currency:USD -id:1]" {
+		t.Fatal("doc.price[0]:", v)
+	}
+	if v, _ = m.ValueForPath("doc.price[1]"); v != "5" {
+		t.Fatal("doc.price[1]:", v)
+	}
+	x, _ := m.Xml()
+	if want := `<doc><price currency="USD" id="1">100</price><price>5</price></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// wildcard path and "#attr" map
+	m = Map{"a": map[string]interface{}{"b": map[string]interface{}{
+		"#attr": map[string]interface{}{"x": "1"}, "v": "text", "unit": "kg"}}}
+	if err = m.PromoteChildAsValue("a.*", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ = m.ValueForPath("a.b"); fmt.Sprint(v) != "map[#attr:map[unit:kg x:1] #text:text]" {
+		t.Fatal("a.b:", v)
+	}
+
+	// errors
+	m = Map{"a": map[string]interface{}{"v": "1", "c": map[string]interface{}{"d": "2"}}}
+	if err = m.PromoteChildAsValue("a", "v"); err == nil {
+		t.Fatal("no error for complex sibling")
+	}
+	if _, ok := m["a"].(map[string]interface{})["v"]; !ok {
+		t.Fatal("modified on error:", m)
+	}
+	if err = m.PromoteChildAsValue("a", "x"); err == nil {
+		t.Fatal("no error for missing child")
+	}
+	if err = m.PromoteChildAsValue("b", "v"); err == nil {
+		t.Fatal("no error for missing path")
+	}
+
+	// an error for a later list member
+	m = Map{"doc": map[string]interface{}{"price": []interface{}{
+		map[string]interface{}{"value": "1", "currency": "USD"},
+		map[string]interface{}{"currency": "EUR"},
+	}}}
+	want := fmt.Sprint(m)
+	if err = m.PromoteChildAsValue("doc.price", "value"); err == nil {
+		t.Fatal("no error for missing child in list member")
+	}
+	if fmt.Sprint(m) != want {
+		t.Fatal("modified on error:", m)
+	}
+
+	// an element with text
+	m = Map{"a": map[string]interface{}{"#text": "t", "v": "1"}}
+	if err = m.PromoteChildAsValue("a", "v"); err == nil {
+		t.Fatal("no error for #text")
+	}
+	if fmt.Sprint(m) != "map[a:map[#text:t v:1]]" {
+		t.Fatal("modified on error:", m)
+	}
+}
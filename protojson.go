@@ -0,0 +1,98 @@
+// protojson.go - encode a Map per the proto3 JSON mapping.
+
+package mxj
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// ProtoJson encodes the Map as JSON using the proto3 JSON mapping conventions for
+// interoperation with protobuf-derived JSON consumers - e.g., gRPC-gateway.
+//	- int64, uint64, int and uint values are encoded as decimal strings.
+//	- NaN, +Inf and -Inf float values are encoded as "NaN", "Infinity" and "-Infinity".
+//	- time.Time values are encoded as RFC 3339 strings in UTC - "1972-01-01T10:00:20.021Z".
+//	- []byte values are encoded as standard base64 strings (as with json.Marshal).
+// The Map is not modified. If option safeEncoding is 'true' then safe encoding of
+// '<', '>' and '&' is preserved, as with mv.Json().
+func (mv Map) ProtoJson(safeEncoding ...bool) ([]byte, error) {
+	m := protoJsonValue(map[string]interface{}(mv)).(map[string]interface{})
+	return Map(m).Json(safeEncoding...)
+}
+
+// protoJsonValue returns a copy of 'v' with the proto3 JSON mapping applied.
+func protoJsonValue(v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		n := make(map[string]interface{}, len(m))
+		for k, vv := range m {
+			n[k] = protoJsonValue(vv)
+		}
+		return n
+	case []interface{}:
+		a := v.([]interface{})
+		n := make([]interface{}, len(a))
+		for i, vv := range a {
+			n[i] = protoJsonValue(vv)
+		}
+		return n
+	case []int64:
+		a := v.([]int64)
+		n := make([]string, len(a))
+		for i, vv := range a {
+			n[i] = strconv.FormatInt(vv, 10)
+		}
+		return n
+	case []uint64:
+		a := v.([]uint64)
+		n := make([]string, len(a))
+		for i, vv := range a {
+			n[i] = strconv.FormatUint(vv, 10)
+		}
+		return n
+	case []int:
+		a := v.([]int)
+		n := make([]string, len(a))
+		for i, vv := range a {
+			n[i] = strconv.Itoa(vv)
+		}
+		return n
+	case []float64:
+		a := v.([]float64)
+		n := make([]interface{}, len(a))
+		for i, vv := range a {
+			n[i] = protoJsonFloat(vv)
+		}
+		return n
+	case int64:
+		return strconv.FormatInt(v.(int64), 10)
+	case uint64:
+		return strconv.FormatUint(v.(uint64), 10)
+	case int:
+		return strconv.Itoa(v.(int))
+	case uint:
+		return strconv.FormatUint(uint64(v.(uint)), 10)
+	case float64:
+		return protoJsonFloat(v.(float64))
+	case float32:
+		return protoJsonFloat(float64(v.(float32)))
+	case time.Time:
+		return v.(time.Time).UTC().Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// protoJsonFloat returns the string encoding for NaN and Inf values.
+func protoJsonFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
@@ -0,0 +1,33 @@
+package mxj
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestProtoJson(t *testing.T) {
+	fmt.Println("\n------------ protojson_test.go")
+	m := Map{
+		"id":    int64(9007199254740993),
+		"count": int32(3),
+		"ratio": 0.5,
+		"nan":   math.NaN(),
+		"inf":   []interface{}{math.Inf(1), math.Inf(-1)},
+		"data":  []byte("hi"),
+		"ts":    time.Date(1972, 1, 1, 10, 0, 20, 21000000, time.UTC),
+		"sub":   map[string]interface{}{"n": uint64(18446744073709551615)},
+	}
+	j, err := m.ProtoJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"count":3,"data":"aGk=","id":"9007199254740993","inf":["Infinity","-Infinity"],"nan":"NaN","ratio":0.5,"sub":{"n":"18446744073709551615"},"ts":"1972-01-01T10:00:20.021Z"}`
+	if string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+	if _, ok := m["id"].(int64); !ok {
+		t.Fatal("Map was modified")
+	}
+}
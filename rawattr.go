@@ -0,0 +1,102 @@
+// rawattr.go - decode attribute values without line ending normalization.
+
+package mxj
+
+import "bytes"
+
+// decode attribute values as they are in the XML source - see DecodeRawAttrValues.
+var decodeRawAttrValues bool
+
+// DecodeRawAttrValues causes NewMapXml() to decode attribute values exactly as they are
+// in the XML source. White space in attribute values is not changed by the decoder except
+// for line endings: per the XML specification "\r\n" and "\r" are normalized to "\n".
+// This is usually right, but it loses the line endings of quirky source files that need
+// to be reproduced exactly. In this mode a tokenizer pass over the document encodes the
+// "\r" characters of attribute values as character references, which are not normalized.
+// If called with no argument, the setting is toggled.
+//	NOTE: this only applies to []byte documents - NewMapXml(), NewMapXmlStrict(), etc. - not
+//	      io.Reader decoding or NewMapXmlSeq... functions; element text is still normalized.
+func DecodeRawAttrValues(b ...bool) {
+	if len(b) == 0 {
+		decodeRawAttrValues = !decodeRawAttrValues
+	} else if len(b) == 1 {
+		decodeRawAttrValues = b[0]
+	}
+}
+
+// rawAttrLineEndings returns 'doc' with each "\r" in an attribute value replaced by "&#13;".
+// Comments, CDATA sections, processing instructions and declarations are skipped.
+func rawAttrLineEndings(doc []byte) []byte {
+	if bytes.IndexByte(doc, '\r') < 0 {
+		return doc
+	}
+	b := make([]byte, 0, len(doc)+64)
+	for i := 0; i < len(doc); {
+		if doc[i] != '<' {
+			b = append(b, doc[i])
+			i++
+			continue
+		}
+		// find the end of the markup
+		var end []byte
+		switch {
+		case bytes.HasPrefix(doc[i:], []byte("<!--")):
+			end = []byte("-->")
+		case bytes.HasPrefix(doc[i:], []byte("<![CDATA[")):
+			end = []byte("]]>")
+		case bytes.HasPrefix(doc[i:], []byte("<?")):
+			end = []byte("?>")
+		case bytes.HasPrefix(doc[i:], []byte("<!")):
+			// DOCTYPE: an internal subset is bracketed
+			n := bytes.IndexByte(doc[i:], '>')
+			if s := bytes.IndexByte(doc[i:], '['); s >= 0 && (n < 0 || s < n) {
+				if e := bytes.Index(doc[i:], []byte("]>")); e >= 0 {
+					n = e + 1
+				}
+			}
+			if n < 0 {
+				return append(b, doc[i:]...)
+			}
+			b = append(b, doc[i:i+n+1]...)
+			i += n + 1
+			continue
+		}
+		if end != nil {
+			n := bytes.Index(doc[i:], end)
+			if n < 0 {
+				return append(b, doc[i:]...)
+			}
+			n += len(end)
+			b = append(b, doc[i:i+n]...)
+			i += n
+			continue
+		}
+
+		b, i = rawAttrTag(b, doc, i)
+	}
+	return b
+}
+
+// rawAttrTag appends the tag at doc[i:] to 'b', encoding the "\r" characters in quoted
+// values, and returns the index of the rest of 'doc'.
+func rawAttrTag(b, doc []byte, i int) ([]byte, int) {
+	var quote byte
+	for ; i < len(doc); i++ {
+		c := doc[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\r' {
+				b = append(b, "&#13;"...)
+				continue
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return append(b, c), i + 1
+		}
+		b = append(b, c)
+	}
+	return b, i
+}
@@ -0,0 +1,41 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDecodeRawAttrValues(t *testing.T) {
+	fmt.Println("\n------------ rawattr_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte("<?xml version=\"1.0\"?>\r\n<!DOCTYPE doc [<!ENTITY e \"x\r\">]>\r\n<!-- a='\r' -->" +
+		"<doc a=\"  x\r\ny\r \" b='\r'><t><![CDATA[c=\"\r\"]]></t><e c=\"1\r\"/></doc>")
+
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.-a"); v != "  x\ny\n " {
+		t.Fatalf("normalized doc.-a: %q", v)
+	}
+
+	DecodeRawAttrValues(true)
+	defer DecodeRawAttrValues(false)
+	if m, err = NewMapXml(data); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{"doc.-a": "  x\r\ny\r ", "doc.-b": "\r", "doc.e.-c": "1\r", "doc.t": "c=\"\n\""} {
+		if v, _ := m.ValueForPath(k); v != want {
+			t.Fatalf("%s: %q want: %q", k, v, want)
+		}
+	}
+
+	// per NewMapXmlStrict
+	if m, err = NewMapXmlStrict([]byte("<doc a=\"x\r\ny\"/>")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.-a"); v != "x\r\ny" {
+		t.Fatalf("strict doc.-a: %q", v)
+	}
+}
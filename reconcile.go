@@ -0,0 +1,109 @@
+// reconcile.go - transform a Map in place to match a target Map.
+
+package mxj
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// Change.Op values.
+const (
+	ChangeAdd     = "add"
+	ChangeReplace = "replace"
+	ChangeRemove  = "remove"
+)
+
+// Change is an operation applied by mv.ReconcileTo().
+//	Op   - ChangeAdd, ChangeReplace or ChangeRemove.
+//	Path - the dot-separated path of the key.
+//	Old  - the previous value; 'nil' for ChangeAdd.
+//	New  - the value that was set; 'nil' for ChangeRemove.
+type Change struct {
+	Op   string
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// ReconcileTo modifies 'mv' in place so that it is equal to 'target' and returns the
+// list of changes that were applied, ordered by path.
+//	- Keys that are in 'target' but not in 'mv' are added.
+//	- Keys that are in 'mv' but not in 'target' are removed.
+//	- Keys whose values differ are replaced. If both values are map[string]interface{},
+//	  the subelements are reconciled, instead; list values, []interface{}, are replaced
+//	  as a whole.
+// The map[string]interface{} and []interface{} values from 'target' are copied, so
+// subsequent modification of 'target' does not modify 'mv'.
+func (mv Map) ReconcileTo(target Map) ([]Change, error) {
+	if mv == nil && len(target) > 0 {
+		return nil, errors.New("cannot reconcile a nil Map")
+	}
+	var changes []Change
+	reconcileMap(map[string]interface{}(mv), map[string]interface{}(target), "", &changes)
+	return changes, nil
+}
+
+func reconcileMap(cur, target map[string]interface{}, path string, changes *[]Change) {
+	keys := make([]string, 0, len(cur)+len(target))
+	for k := range cur {
+		keys = append(keys, k)
+	}
+	for k := range target {
+		if _, ok := cur[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		cv, cok := cur[k]
+		tv, tok := target[k]
+		switch {
+		case !tok:
+			delete(cur, k)
+			*changes = append(*changes, Change{Op: ChangeRemove, Path: p, Old: cv})
+		case !cok:
+			cur[k] = copyValue(tv)
+			*changes = append(*changes, Change{Op: ChangeAdd, Path: p, New: tv})
+		default:
+			cm, cmok := cv.(map[string]interface{})
+			tm, tmok := tv.(map[string]interface{})
+			if cmok && tmok {
+				reconcileMap(cm, tm, p, changes)
+				continue
+			}
+			if !reflect.DeepEqual(cv, tv) {
+				cur[k] = copyValue(tv)
+				*changes = append(*changes, Change{Op: ChangeReplace, Path: p, Old: cv, New: tv})
+			}
+		}
+	}
+}
+
+// copyValue returns a copy of the map[string]interface{} and []interface{} values in 'v';
+// other values are not copied.
+func copyValue(v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		n := make(map[string]interface{}, len(m))
+		for k, vv := range m {
+			n[k] = copyValue(vv)
+		}
+		return n
+	case []interface{}:
+		a := v.([]interface{})
+		n := make([]interface{}, len(a))
+		for i, vv := range a {
+			n[i] = copyValue(vv)
+		}
+		return n
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package mxj
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestReconcileTo(t *testing.T) {
+	fmt.Println("\n------------ reconcile_test.go")
+	cur, err := NewMapXml([]byte(`<doc seq="1"><author>William Gaddis</author><info><pages>976</pages><year>1955</year></info><ref>x</ref></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := NewMapXml([]byte(`<doc seq="1"><author>William T. Gaddis</author><info><pages>976</pages><isbn>1564781259</isbn></info><item>a</item><item>b</item></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := cur.ReconcileTo(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{ChangeReplace, "doc.author", "William Gaddis", "William T. Gaddis"},
+		{ChangeAdd, "doc.info.isbn", nil, "1564781259"},
+		{ChangeRemove, "doc.info.year", "1955", nil},
+		{ChangeAdd, "doc.item", nil, []interface{}{"a", "b"}},
+		{ChangeRemove, "doc.ref", "x", nil},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("got: %v\nwant: %v", changes, want)
+	}
+	if !reflect.DeepEqual(cur, target) {
+		t.Fatal("not reconciled:", cur)
+	}
+
+	// values are copied from target
+	target["doc"].(map[string]interface{})["item"].([]interface{})[0] = "z"
+	if v, _ := cur.ValueForPath("doc.item[0]"); v.(string) != "a" {
+		t.Fatal("target value shared:", v)
+	}
+
+	if changes, _ = cur.ReconcileTo(cur); len(changes) != 0 {
+		t.Fatal("changes for same Map:", changes)
+	}
+}
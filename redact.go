@@ -0,0 +1,44 @@
+// redact.go - mask the values of sensitive keys, e.g., for logging.
+
+package mxj
+
+// RedactedValue replaces the values of redacted keys.
+const RedactedValue = "***"
+
+// Redact returns a copy of the Map with the value of every key that matches one of
+// the 'keys' replaced with RedactedValue. Keys are matched at any depth, including
+// in list members; the entire value for a matched key is replaced, even if it is a
+// map[string]interface{} or []interface{} value.
+//	NOTE: for attributes prefix the label with the attribute prefix character, by default
+//	      a hyphen, '-', e.g., "-password". (See SetAttrPrefix function.)
+func (mv Map) Redact(keys ...string) Map {
+	rk := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		rk[k] = true
+	}
+	return redact(map[string]interface{}(mv), rk).(map[string]interface{})
+}
+
+func redact(v interface{}, keys map[string]bool) interface{} {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		n := make(map[string]interface{}, len(m))
+		for k, vv := range m {
+			if keys[k] {
+				n[k] = RedactedValue
+				continue
+			}
+			n[k] = redact(vv, keys)
+		}
+		return n
+	case []interface{}:
+		a := v.([]interface{})
+		n := make([]interface{}, len(a))
+		for i, vv := range a {
+			n[i] = redact(vv, keys)
+		}
+		return n
+	}
+	return v
+}
@@ -0,0 +1,49 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	fmt.Println("\n------------ redact_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc>
+		<user name="bob" password="secret"><ssn>123-45-6789</ssn></user>
+		<user name="sue" password="secret2"><ssn>987-65-4321</ssn></user>
+		<credentials><key>abc</key></credentials>
+	</doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := m.Redact("-password", "ssn", "credentials")
+	vals, _ := r.ValuesForPath("doc.user.ssn")
+	if len(vals) != 2 {
+		t.Fatal("ssn values:", vals)
+	}
+	for _, v := range vals {
+		if v != RedactedValue {
+			t.Fatal("ssn not redacted:", v)
+		}
+	}
+	vals, _ = r.ValuesForPath("doc.user.-password")
+	for _, v := range vals {
+		if v != RedactedValue {
+			t.Fatal("password not redacted:", v)
+		}
+	}
+	if v, _ := r.ValueForPath("doc.credentials"); v != RedactedValue {
+		t.Fatal("credentials not redacted:", v)
+	}
+	if v, _ := r.ValueForPath("doc.user[1].-name"); v != "sue" {
+		t.Fatal("name changed:", v)
+	}
+
+	// original is unchanged
+	if v, _ := m.ValueForPath("doc.user[0].ssn"); v != "123-45-6789" {
+		t.Fatal("original modified:", v)
+	}
+}
@@ -0,0 +1,113 @@
+// refs.go - replace id/idref references with copies of the referenced elements.
+
+package mxj
+
+import "fmt"
+
+// ExpandRefs replaces each element that has a 'refAttr' attribute with a copy of the
+// element whose 'idAttr' attribute has the same value and returns the number of
+// references expanded. For example, with mv.ExpandRefs("id", "idref")
+//	<doc><item id="x"><name>a</name></item><ref idref="x"/></doc>
+// decodes and expands to the equivalent of
+//	<doc><item id="x"><name>a</name></item><ref id="x"><name>a</name></ref></doc>
+// References in the copied elements are also expanded.
+// 'idAttr' and 'refAttr' are attribute names without the attribute prefix - see
+// PrependAttrWithHyphen. Error is returned, and the Map is not modified, if a reference
+// has no matching element (a dangling reference), if an id value is not unique, or if
+// references are circular.
+func (mv Map) ExpandRefs(idAttr, refAttr string) (int, error) {
+	idKey, refKey := attrPrefix+idAttr, attrPrefix+refAttr
+	index := make(map[string]map[string]interface{})
+	if err := refIndex(map[string]interface{}(mv), idKey, index); err != nil {
+		return 0, err
+	}
+
+	c := copyValue(map[string]interface{}(mv)).(map[string]interface{})
+	x := &refExpander{refKey: refKey, index: index, visiting: make(map[string]bool)}
+	for k, v := range c {
+		vv, err := x.expand(v)
+		if err != nil {
+			return 0, err
+		}
+		c[k] = vv
+	}
+	for k, v := range c {
+		mv[k] = v
+	}
+	return x.n, nil
+}
+
+// refIndex adds the maps in 'v' that have an 'idKey' value to 'index'.
+func refIndex(v interface{}, idKey string, index map[string]map[string]interface{}) error {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		if id, ok := m[idKey]; ok {
+			s := fmt.Sprintf("%v", id)
+			if _, ok := index[s]; ok {
+				return fmt.Errorf("ExpandRefs: id is not unique: %s", s)
+			}
+			index[s] = m
+		}
+		for _, vv := range m {
+			if err := refIndex(vv, idKey, index); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			if err := refIndex(vv, idKey, index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refExpander is the state for mv.ExpandRefs().
+type refExpander struct {
+	refKey   string
+	index    map[string]map[string]interface{}
+	visiting map[string]bool // the ids being expanded, to detect circular references
+	n        int
+}
+
+// expand returns 'v' with the references expanded; maps and lists are modified in place.
+func (x *refExpander) expand(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		if ref, ok := m[x.refKey]; ok {
+			s := fmt.Sprintf("%v", ref)
+			target, ok := x.index[s]
+			if !ok {
+				return nil, fmt.Errorf("ExpandRefs: dangling reference: %s", s)
+			}
+			if x.visiting[s] {
+				return nil, fmt.Errorf("ExpandRefs: circular reference: %s", s)
+			}
+			x.visiting[s] = true
+			defer delete(x.visiting, s)
+			x.n++
+			m = copyValue(target).(map[string]interface{})
+		}
+		for k, vv := range m {
+			e, err := x.expand(vv)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = e
+		}
+		return m, nil
+	case []interface{}:
+		a := v.([]interface{})
+		for i, vv := range a {
+			e, err := x.expand(vv)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = e
+		}
+	}
+	return v, nil
+}
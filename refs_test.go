@@ -0,0 +1,56 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExpandRefs(t *testing.T) {
+	fmt.Println("\n------------ refs_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc><item id="x"><name>a</name><ref idref="y"/></item><item id="y"><name>b</name></item><ref idref="x"/><ref idref="y"/></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := m.ExpandRefs("id", "idref")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 { // doc.ref[0], its copied item/ref, doc.ref[1] and doc.item[0].ref
+		t.Fatal("n:", n)
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc><item id="x"><name>a</name><ref id="y"><name>b</name></ref></item><item id="y"><name>b</name></item>` +
+		`<ref id="x"><name>a</name><ref id="y"><name>b</name></ref></ref><ref id="y"><name>b</name></ref></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// copies are independent
+	if err = m.SetValueForPath("c", "doc.ref[1].name"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.item[1].name"); v != "b" {
+		t.Fatal("target modified:", v)
+	}
+
+	// dangling
+	m, _ = NewMapXml([]byte(`<doc><item id="x"/><ref idref="x"/><ref idref="z"/></doc>`))
+	if _, err = m.ExpandRefs("id", "idref"); err == nil {
+		t.Fatal("no error for dangling reference")
+	}
+	if v, _ := m.ValueForPath("doc.ref[0].-idref"); v != "x" {
+		t.Fatal("Map modified on error:", m)
+	}
+
+	// circular
+	m, _ = NewMapXml([]byte(`<doc><item id="x"><ref idref="x"/></item></doc>`))
+	if _, err = m.ExpandRefs("id", "idref"); err == nil {
+		t.Fatal("no error for circular reference")
+	}
+}
@@ -0,0 +1,67 @@
+// rekey.go - rename the keys of a Map using a function.
+
+package mxj
+
+import "fmt"
+
+// Rekey renames every key of the Map - at all depths, including those of maps that
+// are list members - to the value that 'fn' returns for it, and returns the number of
+// keys renamed. The 'path' argument is the dot-separated path of the key's parent in
+// the Map before it's rekeyed, without list indexes; it's "" for the top level keys.
+// Attribute keys are passed with the attribute prefix; e.g., to lowercase all keys:
+//	n, err := mv.Rekey(func(path, key string) string { return strings.ToLower(key) })
+// Error is returned, and the Map is not modified, if two keys of a map would have
+// the same name.
+func (mv Map) Rekey(fn func(path, key string) string) (int, error) {
+	var r []rekeyOp
+	if err := rekeyValue(map[string]interface{}(mv), "", fn, &r); err != nil {
+		return 0, err
+	}
+	// remove all the old keys first, so keys can be swapped
+	for _, v := range r {
+		delete(v.m, v.old)
+	}
+	for _, v := range r {
+		v.m[v.new] = v.val
+	}
+	return len(r), nil
+}
+
+// rekeyOp is a rename of a key of 'm'.
+type rekeyOp struct {
+	m        map[string]interface{}
+	old, new string
+	val      interface{}
+}
+
+func rekeyValue(v interface{}, path string, fn func(string, string) string, r *[]rekeyOp) error {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		names := make(map[string]string, len(m)) // new:old
+		for k, vv := range m {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if err := rekeyValue(vv, p, fn, r); err != nil {
+				return err
+			}
+			nk := fn(path, k)
+			if old, ok := names[nk]; ok {
+				return fmt.Errorf("Rekey: keys %q and %q would both be %q at path: %s", old, k, nk, path)
+			}
+			names[nk] = k
+			if nk != k {
+				*r = append(*r, rekeyOp{m: m, old: k, new: nk, val: vv})
+			}
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			if err := rekeyValue(vv, path, fn, r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package mxj
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRekey(t *testing.T) {
+	fmt.Println("\n------------ rekey_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<Doc ID="1"><ns_Item><ns_Name>a</ns_Name></ns_Item><ns_Item><ns_Name>b</ns_Name></ns_Item></Doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	n, err := m.Rekey(func(path, key string) string {
+		if key == "ns_Name" {
+			paths = append(paths, path)
+		}
+		return strings.ToLower(strings.TrimPrefix(key, "ns_"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 { // Doc, -ID, ns_Item, ns_Name x2
+		t.Fatal("renamed:", n)
+	}
+	if fmt.Sprint(paths) != "[Doc.ns_Item Doc.ns_Item]" {
+		t.Fatal("paths:", paths)
+	}
+	x, _ := m.Xml()
+	if want := `<doc id="1"><item><name>a</name></item><item><name>b</name></item></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// swap keys
+	m = Map{"a": 1, "b": 2}
+	if n, err = m.Rekey(func(_, k string) string { return map[string]string{"a": "b", "b": "a"}[k] }); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || m["a"] != 2 || m["b"] != 1 {
+		t.Fatal("n:", n, "m:", m)
+	}
+
+	// collision
+	m = Map{"doc": map[string]interface{}{"A": 1, "a": 2}}
+	if _, err = m.Rekey(func(_, k string) string { return strings.ToLower(k) }); err == nil {
+		t.Fatal("no error for collision")
+	}
+	if _, ok := m["doc"].(map[string]interface{})["A"]; !ok {
+		t.Fatal("modified on error:", m)
+	}
+}
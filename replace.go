@@ -0,0 +1,52 @@
+// replace.go - replace every occurrence of a value in a Map.
+
+package mxj
+
+// ReplaceValue replaces every leaf value of the Map that is equal to 'old' with 'new',
+// including the members of list values, and returns the number of values replaced.
+// The values of all keys - attributes and "#text" as well as elements - are compared;
+// map[string]interface{} and []interface{} values are not leaf values and are walked.
+// Numeric values of different types are equal if they have the same value - e.g.,
+// int(1) and float64(1) - otherwise values are compared with reflect.DeepEqual.
+// The Map is modified in place.
+func (mv Map) ReplaceValue(old, new interface{}) int {
+	return replaceValue(map[string]interface{}(mv), old, new)
+}
+
+func replaceValue(v, old, new interface{}) int {
+	var n int
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range v {
+			if isLeafValue(vv) {
+				if valuesEqual(vv, old) {
+					v[k] = new
+					n++
+				}
+				continue
+			}
+			n += replaceValue(vv, old, new)
+		}
+	case []interface{}:
+		for i, vv := range v {
+			if isLeafValue(vv) {
+				if valuesEqual(vv, old) {
+					v[i] = new
+					n++
+				}
+				continue
+			}
+			n += replaceValue(vv, old, new)
+		}
+	}
+	return n
+}
+
+// isLeafValue reports whether 'v' is not a map[string]interface{} or []interface{} value.
+func isLeafValue(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
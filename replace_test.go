@@ -0,0 +1,43 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReplaceValue(t *testing.T) {
+	fmt.Println("\n------------ replace_test.go")
+	m := Map{"doc": map[string]interface{}{
+		"-owner": "alice",
+		"name":   "alice",
+		"friend": []interface{}{"bob", "alice", map[string]interface{}{"#text": "alice", "-id": float64(1)}},
+		"other":  "alice smith",
+		"count":  float64(1),
+	}}
+
+	if n := m.ReplaceValue("alice", "user"); n != 4 {
+		t.Fatal("n:", n)
+	}
+	want := Map{"doc": map[string]interface{}{
+		"-owner": "user",
+		"name":   "user",
+		"friend": []interface{}{"bob", "user", map[string]interface{}{"#text": "user", "-id": float64(1)}},
+		"other":  "alice smith",
+		"count":  float64(1),
+	}}
+	if !Equal(m, want) {
+		t.Fatal("got:", m, "want:", want)
+	}
+
+	// numeric values of different types
+	if n := m.ReplaceValue(1, "one"); n != 2 {
+		t.Fatal("n:", n)
+	}
+	if v, _ := m.ValueForPath("doc.friend[2].-id"); v != "one" {
+		t.Fatal("doc.friend[2].-id:", v)
+	}
+
+	if n := m.ReplaceValue("none", "x"); n != 0 {
+		t.Fatal("n:", n)
+	}
+}
@@ -0,0 +1,65 @@
+// rootattrs.go - read the root element attributes without decoding the document.
+
+package mxj
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// XmlRootAttributes reads the XML from 'r' up to the first start element and returns
+// its attributes and tag name; the rest of the document is not read or checked, so
+// routing decisions can be made on the root attributes of large documents cheaply.
+// The attribute keys do not have the attribute prefix - as with DecodeAttrsAsMap -
+// and name space declarations are keyed as "xmlns" and "xmlns:prefix".
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+//	NOTE: 'r' may be read beyond the start element - it is buffered if it is not an io.ByteReader.
+func XmlRootAttributes(r io.Reader, cast ...bool) (map[string]interface{}, string, error) {
+	var c bool
+	if len(cast) == 1 {
+		c = cast[0]
+	}
+	return xmlRootAttributes(r, c)
+}
+
+func xmlRootAttributes(rdr io.Reader, r bool) (map[string]interface{}, string, error) {
+	p := xml.NewDecoder(rdr)
+	if CustomDecoder != nil {
+		useCustomDecoder(p)
+	} else {
+		p.CharsetReader = XmlCharsetReader
+	}
+	for {
+		var t xml.Token
+		var err error
+		if preserveNsPrefix {
+			t, err = p.RawToken()
+		} else {
+			t, err = p.Token()
+		}
+		if err == io.EOF {
+			return nil, "", errors.New("no root element")
+		} else if err != nil {
+			return nil, "", errors.New("xml.Decoder.Token() - " + err.Error())
+		}
+		se, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		tag := xmlName(se.Name)
+		if lowerCase || LowercaseKeys {
+			tag = strings.ToLower(tag)
+		}
+		attrs := make(map[string]interface{}, len(se.Attr))
+		for _, a := range se.Attr {
+			key := xmlAttrName(a.Name)
+			if lowerCase || LowercaseKeys {
+				key = strings.ToLower(key)
+			}
+			attrs[key] = cast(a.Value, r, attrPrefix+key)
+		}
+		return attrs, tag, nil
+	}
+}
@@ -0,0 +1,39 @@
+package mxj
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestXmlRootAttributes(t *testing.T) {
+	fmt.Println("\n------------ rootattrs_test.go")
+	// the body is never read, so it needn't be well-formed
+	doc := `<?xml version="1.0"?><!-- routing --><msg xmlns:p="urn:p" type="order" seq="12"><body>` + strings.Repeat("x", 10000)
+	attrs, tag, err := XmlRootAttributes(strings.NewReader(doc), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "msg" {
+		t.Fatal("tag:", tag)
+	}
+	if len(attrs) != 3 || attrs["type"] != "order" || attrs["seq"] != float64(12) || attrs["xmlns:p"] != "urn:p" {
+		t.Fatal("attrs:", attrs)
+	}
+
+	attrs, _, err = XmlRootAttributes(strings.NewReader(`<msg seq="12"/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs["seq"] != "12" {
+		t.Fatal("attrs:", attrs)
+	}
+
+	if _, _, err = XmlRootAttributes(strings.NewReader(`<!-- none -->`)); err == nil {
+		t.Fatal("no error for no root element")
+	}
+	if _, _, err = XmlRootAttributes(strings.NewReader(`<msg seq=12>`)); err == nil || err == io.EOF {
+		t.Fatal("no error for bad start element:", err)
+	}
+}
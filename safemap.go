@@ -0,0 +1,170 @@
+// safemap.go - a Map wrapper for concurrent access.
+
+package mxj
+
+import (
+	"sync"
+)
+
+// SafeMap wraps a Map value with a sync.RWMutex so that it can be shared by goroutines
+// that read and update it concurrently. Methods that read the Map hold the read lock;
+// methods that modify it hold the write lock.
+//	NOTE: values that are returned - map[string]interface{} and []interface{} - are
+//	      references into the Map. Do not modify them outside of sm.Update(); use
+//	      sm.Copy() if an independent value is required.
+type SafeMap struct {
+	mu sync.RWMutex
+	mv Map
+}
+
+// NewSafeMap returns a SafeMap that wraps 'mv'. The Map should not be
+// accessed directly once it is wrapped.
+func NewSafeMap(mv Map) *SafeMap {
+	if mv == nil {
+		mv = make(Map)
+	}
+	return &SafeMap{mv: mv}
+}
+
+// NewSafeMapXml decodes 'xmlVal' as with NewMapXml() and returns it as a SafeMap.
+func NewSafeMapXml(xmlVal []byte, cast ...bool) (*SafeMap, error) {
+	mv, err := NewMapXml(xmlVal, cast...)
+	if err != nil {
+		return nil, err
+	}
+	return NewSafeMap(mv), nil
+}
+
+// NewSafeMapJson decodes 'jsonVal' as with NewMapJson() and returns it as a SafeMap.
+func NewSafeMapJson(jsonVal []byte) (*SafeMap, error) {
+	mv, err := NewMapJson(jsonVal)
+	if err != nil {
+		return nil, err
+	}
+	return NewSafeMap(mv), nil
+}
+
+// Read calls 'fn' with the wrapped Map while holding the read lock.
+// The Map must not be modified or retained by 'fn'.
+func (sm *SafeMap) Read(fn func(Map)) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	fn(sm.mv)
+}
+
+// Update calls 'fn' with the wrapped Map while holding the write lock,
+// so compound modifications are atomic. It returns the error from 'fn'.
+func (sm *SafeMap) Update(fn func(Map) error) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return fn(sm.mv)
+}
+
+// Copy returns a deep copy of the wrapped Map, as with mv.Copy().
+func (sm *SafeMap) Copy() (Map, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.Copy()
+}
+
+// ---------------------------- read methods -----------------------------
+
+// Exists - as with mv.Exists().
+func (sm *SafeMap) Exists(path string, subkeys ...string) (bool, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.Exists(path, subkeys...)
+}
+
+// ValueForKey - as with mv.ValueForKey().
+func (sm *SafeMap) ValueForKey(key string, subkeys ...string) (interface{}, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.ValueForKey(key, subkeys...)
+}
+
+// ValuesForKey - as with mv.ValuesForKey().
+func (sm *SafeMap) ValuesForKey(key string, subkeys ...string) ([]interface{}, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.ValuesForKey(key, subkeys...)
+}
+
+// ValueForPath - as with mv.ValueForPath().
+func (sm *SafeMap) ValueForPath(path string) (interface{}, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.ValueForPath(path)
+}
+
+// ValueForPathString - as with mv.ValueForPathString().
+func (sm *SafeMap) ValueForPathString(path string) (string, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.ValueForPathString(path)
+}
+
+// ValuesForPath - as with mv.ValuesForPath().
+func (sm *SafeMap) ValuesForPath(path string, subkeys ...string) ([]interface{}, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.ValuesForPath(path, subkeys...)
+}
+
+// Xml - as with mv.Xml().
+func (sm *SafeMap) Xml(rootTag ...string) ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.Xml(rootTag...)
+}
+
+// XmlIndent - as with mv.XmlIndent().
+func (sm *SafeMap) XmlIndent(prefix, indent string, rootTag ...string) ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.XmlIndent(prefix, indent, rootTag...)
+}
+
+// Json - as with mv.Json().
+func (sm *SafeMap) Json(safeEncoding ...bool) ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.Json(safeEncoding...)
+}
+
+// JsonIndent - as with mv.JsonIndent().
+func (sm *SafeMap) JsonIndent(prefix, indent string, safeEncoding ...bool) ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.mv.JsonIndent(prefix, indent, safeEncoding...)
+}
+
+// ---------------------------- write methods -----------------------------
+
+// SetValueForPath - as with mv.SetValueForPath().
+func (sm *SafeMap) SetValueForPath(value interface{}, path string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.mv.SetValueForPath(value, path)
+}
+
+// UpdateValuesForPath - as with mv.UpdateValuesForPath().
+func (sm *SafeMap) UpdateValuesForPath(newVal interface{}, path string, subkeys ...string) (int, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.mv.UpdateValuesForPath(newVal, path, subkeys...)
+}
+
+// Remove - as with mv.Remove().
+func (sm *SafeMap) Remove(path string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.mv.Remove(path)
+}
+
+// RenameKey - as with mv.RenameKey().
+func (sm *SafeMap) RenameKey(path string, newName string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.mv.RenameKey(path, newName)
+}
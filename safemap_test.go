@@ -0,0 +1,54 @@
+package mxj
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSafeMap(t *testing.T) {
+	fmt.Println("\n------------ safemap_test.go")
+	sm, err := NewSafeMapXml([]byte(`<doc><count>0</count><name>test</name></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := sm.ValueForPath("doc.name"); err != nil {
+				t.Error(err)
+			}
+			if _, err := sm.Xml(); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			if err := sm.SetValueForPath(i, "doc.count"); err != nil {
+				t.Error(err)
+			}
+			err := sm.Update(func(mv Map) error {
+				n, _ := mv.ValueForPath("doc.count")
+				return mv.SetValueForPath(fmt.Sprint(n), "doc.last")
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if ok, _ := sm.Exists("doc.last"); !ok {
+		t.Fatal("doc.last not set")
+	}
+	c, err := sm.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.ValueForPath("doc.name"); v.(string) != "test" {
+		t.Fatal("doc.name:", v)
+	}
+}
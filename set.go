@@ -1,26 +1,139 @@
 package mxj
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 )
 
-// Sets the value for the path
+// SetValueForPath sets 'value' as the value for the dot-separated 'path' - e.g.,
+// "doc.books.book.2.price" - creating map[string]interface{} values for the keys
+// that don't exist. A list member is referenced by a numeric key following the
+// list's key or by an index - e.g., "doc.books.book[2].price"; for a list that
+// is not referenced by index the first member is used, as with mv.ValueForPath().
+// Error is returned, and the Map is not modified, if a value on the path is not a
+// map[string]interface{} value - e.g., a string - or an index is out of range.
 func (mv Map) SetValueForPath(value interface{}, path string) error {
-	pathAry := strings.Split(path, ".")
-	parentPathAry := pathAry[0 : len(pathAry)-1]
-	parentPath := strings.Join(parentPathAry, ".")
-
-	val, err := mv.ValueForPath(parentPath)
+	keys, err := parsePath(path)
 	if err != nil {
 		return err
 	}
-	if val == nil {
-		return nil // we just ignore the request if there's no val
+	if len(keys) == 0 {
+		return errors.New("SetValueForPath: no key in path")
+	}
+
+	m := map[string]interface{}(mv)
+	for i := 0; i < len(keys); i++ {
+		k := keys[i]
+		last := i == len(keys)-1
+		v, ok := m[k.name]
+		if !ok {
+			if k.isArray {
+				return fmt.Errorf("SetValueForPath: no list for %s[%d] in %s", k.name, k.position, path)
+			}
+			if last {
+				m[k.name] = value
+				return nil
+			}
+			// check the rest of the path before creating anything
+			for _, kk := range keys[i+1:] {
+				if kk.isArray {
+					return fmt.Errorf("SetValueForPath: no list for %s[%d] in %s", kk.name, kk.position, path)
+				}
+			}
+			for _, kk := range keys[i : len(keys)-1] {
+				vm := make(map[string]interface{})
+				m[kk.name] = vm
+				m = vm
+			}
+			m[keys[len(keys)-1].name] = value
+			return nil
+		}
+
+		// the list member, per index or a numeric key
+		a, isList := v.([]interface{})
+		n := -1
+		if k.isArray {
+			if !isList {
+				return fmt.Errorf("SetValueForPath: value for %s in %s is not a list", k.name, path)
+			}
+			n = k.position
+		} else if isList && !last && !keys[i+1].isArray {
+			if nn, err := strconv.Atoi(keys[i+1].name); err == nil {
+				n = nn
+				i++
+				last = i == len(keys)-1
+			}
+		}
+		if n >= 0 {
+			if n >= len(a) {
+				return fmt.Errorf("SetValueForPath: index %d for %s in %s out of range", n, k.name, path)
+			}
+			if last {
+				a[n] = value
+				return nil
+			}
+			v = a[n]
+		} else if last {
+			m[k.name] = value
+			return nil
+		} else if isList && len(a) > 0 {
+			v = a[0]
+		}
+
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("SetValueForPath: value for %s in %s is not a map", k.name, path)
+		}
+		m = vm
+	}
+	return nil
+}
+
+// MoveForPath removes the value for the 'src' path and sets it as the value for the
+// 'dst' path; maps are created for the keys of 'dst' that don't exist. Error is returned,
+// and the Map is not modified, if 'src' doesn't exist, if a subpath of 'dst' is not a
+// map[string]interface{} value, or if 'dst' is beneath 'src'.
+//	NOTE: the paths must be dot-separated lists of keys; indexed references are not supported.
+func (mv Map) MoveForPath(src, dst string) error {
+	if dst == src || strings.HasPrefix(dst, src+".") {
+		return fmt.Errorf("MoveForPath: destination %s is in source %s", dst, src)
+	}
+	sm, err := prevValueByPath(map[string]interface{}(mv), src)
+	if err != nil {
+		return err
 	}
 
-	key := pathAry[len(pathAry)-1]
-	cVal := val.(map[string]interface{})
-	cVal[key] = value
+	// check the destination before creating anything
+	keys := strings.Split(dst, ".")
+	dm := map[string]interface{}(mv)
+	var create bool
+	for _, k := range keys[:len(keys)-1] {
+		v, ok := dm[k]
+		if !ok {
+			create = true
+			break
+		}
+		if dm, ok = v.(map[string]interface{}); !ok {
+			return fmt.Errorf("MoveForPath: value for %s in destination %s is not a map", k, dst)
+		}
+	}
+	if create {
+		dm = map[string]interface{}(mv)
+		for _, k := range keys[:len(keys)-1] {
+			v, ok := dm[k].(map[string]interface{})
+			if !ok {
+				v = make(map[string]interface{})
+				dm[k] = v
+			}
+			dm = v
+		}
+	}
 
+	key := lastKey(src)
+	v := sm[key]
+	delete(sm, key)
+	dm[keys[len(keys)-1]] = v
 	return nil
 }
@@ -41,3 +41,79 @@ func TestSetValueForPath(t *testing.T) {
 		t.Fatal("existig key's value hasn't changed")
 	}
 }
+
+func TestSetValueForPathCreate(t *testing.T) {
+	m, err := NewMapJson([]byte(`{"books":{"book":[{"price":1},{"price":2},{"price":3}]},"title":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// new deep path
+	if err = m.SetValueForPath("a", "doc.meta.author.name"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.meta.author.name"); v != "a" {
+		t.Fatal("doc.meta.author.name:", v)
+	}
+	// overwrite leaves
+	if err = m.SetValueForPath("b", "doc.meta.author.name"); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.SetValueForPath("y", "title"); err != nil {
+		t.Fatal(err)
+	}
+	// list members
+	if err = m.SetValueForPath(30, "books.book.2.price"); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.SetValueForPath(20, "books.book[1].price"); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.SetValueForPath("new", "books.book.0.tag.name"); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.SetValueForPath(map[string]interface{}{"price": 4}, "books.book.0"); err != nil {
+		t.Fatal(err)
+	}
+	j, _ := m.Json()
+	want := `{"books":{"book":[{"price":4},{"price":20},{"price":30}]},"doc":{"meta":{"author":{"name":"b"}}},"title":"y"}`
+	if string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+
+	for _, p := range []string{"title.x", "title.x.y", "books.book.3.price", "books.book[5].price",
+		"title[0].x", "none[0]", "none.x[1].y", "books.book.1.price.x", "a[x]"} {
+		if err = m.SetValueForPath(0, p); err == nil {
+			t.Fatal("no error for:", p)
+		}
+	}
+	if jj, _ := m.Json(); string(jj) != want {
+		t.Fatal("modified on error:", string(jj))
+	}
+}
+
+func TestMoveForPath(t *testing.T) {
+	m, err := NewMapJson([]byte(`{"env":{"header":{"id":"1"},"body":{"msg":"hi"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.MoveForPath("env.body.msg", "msg"); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.MoveForPath("env.header", "meta.env.header"); err != nil {
+		t.Fatal(err)
+	}
+	j, _ := m.Json()
+	if want := `{"env":{"body":{}},"meta":{"env":{"header":{"id":"1"}}},"msg":"hi"}`; string(j) != want {
+		t.Fatal("got:", string(j), "want:", want)
+	}
+
+	for _, v := range [][2]string{{"none", "x"}, {"msg", "msg.x"}, {"env", "env.body.x"}, {"meta", "msg.x.y"}} {
+		if err = m.MoveForPath(v[0], v[1]); err == nil {
+			t.Fatal("no error for:", v)
+		}
+	}
+	if jj, _ := m.Json(); string(jj) != string(j) {
+		t.Fatal("modified on error:", string(jj))
+	}
+}
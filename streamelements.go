@@ -0,0 +1,76 @@
+// streamelements.go - decode the elements with a tag from anywhere in an XML stream.
+
+package mxj
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// StreamElements reads the XML on 'r' and calls 'fn' with each element that has the tag
+// 'tag', wherever it occurs in the document, decoded as a Map as with NewMapXmlReader() -
+// e.g., for 'tag' "item" the Map is {"item":{...}}. The rest of the document is only
+// tokenized, so memory use is bounded by the size of the matching elements rather than
+// the document. A matching element within a matching element is part of the enclosing
+// element's Map, it is not passed to 'fn' separately.
+// The 'tag' is matched with the element name as it is decoded - e.g., "ns:item" if
+// PreserveNamespacePrefixes(true) is set, otherwise the local name - before any key
+// case conversion. Reading stops if 'fn' returns an error - which is returned - or on a
+// decoding error; at io.EOF 'nil' is returned.
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+func StreamElements(r io.Reader, tag string, fn func(Map) error, cast ...bool) error {
+	var c bool
+	if len(cast) == 1 {
+		c = cast[0]
+	}
+	p := xml.NewDecoder(r)
+	if CustomDecoder != nil {
+		useCustomDecoder(p)
+	} else {
+		p.CharsetReader = XmlCharsetReader
+	}
+
+	var space []bool // xml:space="preserve" in scope for the enclosing elements
+	for {
+		var t xml.Token
+		var err error
+		if preserveNsPrefix {
+			t, err = p.RawToken()
+		} else {
+			t, err = p.Token()
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.New("xml.Decoder.Token() - " + err.Error())
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			var s bool
+			if len(space) > 0 {
+				s = space[len(space)-1]
+			}
+			s = xmlSpacePreserve(tt.Attr, s)
+			if xmlName(tt.Name) != tag {
+				space = append(space, s)
+				continue
+			}
+			// xmlToMapParser consumes the element's end tag
+			m, err := xmlToMapParser(xmlName(tt.Name), tt.Attr, p, c, nil, s, nil)
+			if err != nil {
+				if err == io.EOF {
+					return errors.New("xml.Decoder.Token() - unexpected EOF")
+				}
+				return err
+			}
+			if err = fn(Map(m)); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if len(space) > 0 {
+				space = space[:len(space)-1]
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package mxj
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStreamElements(t *testing.T) {
+	fmt.Println("\n------------ streamelements_test.go")
+	PrependAttrWithHyphen(true)
+	data := []byte(`<?xml version="1.0"?>
+<feed>
+	<title>f</title>
+	<item id="1"><name>a</name></item>
+	<group>
+		<item id="2"><name>b</name><item>nested</item></item>
+	</group>
+	<item id="3"/>
+</feed>`)
+
+	var got []Map
+	err := StreamElements(bytes.NewReader(data), "item", func(m Map) error {
+		got = append(got, m)
+		return nil
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Map{
+		{"item": map[string]interface{}{"-id": float64(1), "name": "a"}},
+		{"item": map[string]interface{}{"-id": float64(2), "name": "b", "item": "nested"}},
+		{"item": map[string]interface{}{"-id": float64(3)}},
+	}
+	if len(got) != len(want) {
+		t.Fatal("got:", got, "want:", want)
+	}
+	for i := range got {
+		if !Equal(got[i], want[i]) {
+			t.Fatal(i, "got:", got[i], "want:", want[i])
+		}
+	}
+
+	// stop on the 'fn' error
+	stop := errors.New("stop")
+	var n int
+	err = StreamElements(bytes.NewReader(data), "item", func(m Map) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Fatal("err:", err, "n:", n)
+	}
+
+	if err = StreamElements(bytes.NewReader(data[:len(data)-20]), "item", func(Map) error { return nil }); err == nil {
+		t.Fatal("no error for truncated XML")
+	}
+	if err = StreamElements(bytes.NewReader([]byte(`<feed><item>a`)), "item", func(Map) error { return nil }); err == nil {
+		t.Fatal("no error for truncated element")
+	}
+}
@@ -8,7 +8,9 @@
 package mxj
 
 import (
+	"bytes"
 	"encoding/xml"
+	"io"
 )
 
 // CustomDecoder can be used to specify xml.Decoder attribute
@@ -28,3 +30,55 @@ func useCustomDecoder(d *xml.Decoder) {
 	d.DefaultSpace = CustomDecoder.DefaultSpace
 }
 
+// NewMapXmlStrict is NewMapXml() using a strict xml.Decoder, regardless of the
+// CustomDecoder setting. Malformed XML - e.g., a raw '&' character, an undefined
+// entity or mismatched tags - is returned as an error rather than being passed
+// through into the Map values. The CustomDecoder CharsetReader and Entity values
+// are still used, if CustomDecoder != nil.
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+func NewMapXmlStrict(xmlVal []byte, cast ...bool) (Map, error) {
+	var r bool
+	if len(cast) == 1 {
+		r = cast[0]
+	}
+	if decodeRawAttrValues {
+		xmlVal = rawAttrLineEndings(xmlVal)
+	}
+	b, pos := sourcePositions(bytes.NewReader(xmlVal))
+	p := xml.NewDecoder(b)
+	if CustomDecoder != nil {
+		useCustomDecoder(p)
+		p.Strict = true
+		p.AutoClose = nil
+	} else {
+		p.CharsetReader = XmlCharsetReader
+	}
+	return xmlToMapParser("", nil, p, r, nil, false, pos)
+}
+
+// NewMapXmlWithDecoder is NewMapXmlReader() with a hook to configure the xml.Decoder
+// before the XML is parsed. The decoder is initialized per CustomDecoder or
+// XmlCharsetReader, as usual; 'configure', if not nil, can then set any xml.Decoder
+// field - Strict, AutoClose, Entity, CharsetReader, DefaultSpace - for this call only.
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+func NewMapXmlWithDecoder(xmlReader io.Reader, configure func(*xml.Decoder), cast ...bool) (Map, error) {
+	var r bool
+	if len(cast) == 1 {
+		r = cast[0]
+	}
+	// see NewMapXmlReader
+	if _, ok := xmlReader.(io.ByteReader); !ok {
+		xmlReader = myByteReader(xmlReader)
+	}
+	xmlReader, pos := sourcePositions(limitXmlReader(xmlReader))
+	p := xml.NewDecoder(xmlReader)
+	if CustomDecoder != nil {
+		useCustomDecoder(p)
+	} else {
+		p.CharsetReader = XmlCharsetReader
+	}
+	if configure != nil {
+		configure(p)
+	}
+	return xmlToMapParser("", nil, p, r, nil, false, pos)
+}
@@ -3,6 +3,7 @@ package mxj
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -46,3 +47,49 @@ func TestStrictModeFail(t *testing.T) {
 	fmt.Println("OK")
 }
 
+func TestNewMapXmlStrict(t *testing.T) {
+	data := []byte(`<document> <name>Bill & Hallett</name> <salute>Duc &amp; 123xx</salute> </document>`)
+
+	CustomDecoder = &xml.Decoder{Strict: false}
+	defer func() { CustomDecoder = nil }()
+	if _, err := NewMapXmlStrict(data); err == nil {
+		t.Fatal("error not caught: NewMapXmlStrict")
+	}
+	if _, err := NewMapXmlStrict([]byte(`<doc>&undefined;</doc>`)); err == nil {
+		t.Fatal("error not caught for undefined entity")
+	}
+
+	CustomDecoder = &xml.Decoder{Strict: false, Entity: map[string]string{"co": "Company"}}
+	m, err := NewMapXmlStrict([]byte(`<doc>&co; &amp; Sons</doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc"); v.(string) != "Company & Sons" {
+		t.Fatal("doc:", v)
+	}
+}
+
+func TestNewMapXmlWithDecoder(t *testing.T) {
+	data := `<doc><name>Bill &copy; Hallett</name><p>a<br>b</p></doc>`
+	if _, err := NewMapXmlWithDecoder(strings.NewReader(data), nil); err == nil {
+		t.Fatal("no error for undefined entity")
+	}
+
+	m, err := NewMapXmlWithDecoder(strings.NewReader(data), func(d *xml.Decoder) {
+		d.Entity = map[string]string{"copy": "(c)"}
+		d.AutoClose = xml.HTMLAutoClose
+		d.Strict = false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.name"); v != "Bill (c) Hallett" {
+		t.Fatal("doc.name:", v)
+	}
+	if _, err = m.ValueForPath("doc.p.br"); err != nil {
+		t.Fatal("doc.p.br:", err, m)
+	}
+	if CustomDecoder != nil {
+		t.Fatal("CustomDecoder modified")
+	}
+}
@@ -0,0 +1,50 @@
+// stringmap.go - return the simple values of a map as strings.
+
+package mxj
+
+import "fmt"
+
+// ToMapStringString returns the simple element values of the map at 'path' - strings,
+// numbers and booleans formatted per "%v" - keyed by their labels; attribute values are
+// included with the attribute prefix. An empty element, "", or 'nil' value is "". If 'path'
+// is "", the top level values of the Map are returned. Thus
+//	<config><host>a.b.c</host><port>8080</port></config>
+// with mv.ToMapStringString("config") returns:
+//	map["host":"a.b.c", "port":"8080"]
+// Values that are maps or lists cause an error to be returned unless the optional argument
+// 'skipComplex' is 'true', in which case they are not included.
+func (mv Map) ToMapStringString(path string, skipComplex ...bool) (map[string]string, error) {
+	var skip bool
+	if len(skipComplex) == 1 {
+		skip = skipComplex[0]
+	}
+	m := map[string]interface{}(mv)
+	if path != "" {
+		v, err := mv.ValueForPath(path)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if m, ok = v.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("ToMapStringString: value for path %s is not a map: %T", path, v)
+		}
+	}
+
+	s := make(map[string]string, len(m))
+	for k, v := range m {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			if skip {
+				continue
+			}
+			return nil, fmt.Errorf("ToMapStringString: value for %s is not a simple value: %T", k, v)
+		case nil:
+			s[k] = ""
+		case []byte:
+			s[k] = string(v.([]byte))
+		default:
+			s[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,40 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToMapStringString(t *testing.T) {
+	fmt.Println("\n------------ stringmap_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<config id="1"><host>a.b.c</host><port>8080</port><debug>true</debug><empty/><opts><x>1</x></opts></config>`)
+	m, err := NewMapXml(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.ToMapStringString("config"); err == nil {
+		t.Fatal("no error for complex value")
+	}
+	s, err := m.ToMapStringString("config", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(s); got != "map[-id:1 debug:true empty: host:a.b.c port:8080]" {
+		t.Fatal("got:", got)
+	}
+
+	if s, err = (Map{"a": 1.5, "b": nil}).ToMapStringString(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(s); got != "map[a:1.5 b:]" {
+		t.Fatal("got:", got)
+	}
+	if _, err = m.ToMapStringString("config.host"); err == nil {
+		t.Fatal("no error for non-map path")
+	}
+	if _, err = m.ToMapStringString("none"); err == nil {
+		t.Fatal("no error for missing path")
+	}
+}
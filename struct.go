@@ -7,7 +7,10 @@ package mxj
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	// "github.com/fatih/structs"
 )
@@ -52,3 +55,188 @@ func (mv Map) Struct(structPtr interface{}) error {
 
 	return json.Unmarshal(j, structPtr)
 }
+
+// SliceToStructs decodes the values for 'path' into the slice of structures referenced
+// by 'slicePtr' - e.g., a *[]MyStruct or *[]*MyStruct value - one structure per value.
+// It is normally used to decode a list of repeated elements; if 'path' has a single
+// value then the slice will have a single member.
+//	Structure fields are loaded by reflection rather than a JSON round trip:
+//	- Only public fields are loaded. The key is the field name or the name in the
+//	  `json:"name"` tag, if any; a `json:"-"` tag causes the field to be skipped.
+//	  As with encoding/json, a key is matched case-insensitively if there is no exact match.
+//	- string and json.Number values are parsed for numeric and bool fields, so
+//	  Map values decoded from XML without casting can be loaded.
+//	- Nested structures, slices and pointers to them are loaded recursively; a
+//	  singleton value is loaded as a one-member slice.
+//	Error is returned if 'slicePtr' is not a pointer to a slice of structures, if a path
+//	value is not a map[string]interface{} value, or if a value cannot be loaded into a field.
+func (mv Map) SliceToStructs(path string, slicePtr interface{}) error {
+	pv := reflect.ValueOf(slicePtr)
+	if pv.Kind() != reflect.Ptr || pv.Elem().Kind() != reflect.Slice {
+		return errors.New("mv.SliceToStructs() error: argument is not type Ptr to Slice")
+	}
+	sv := pv.Elem()
+	et := sv.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return errors.New("mv.SliceToStructs() error: argument is not type Ptr to Slice of Struct")
+	}
+
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return err
+	}
+	s := reflect.MakeSlice(sv.Type(), 0, len(vals))
+	for i, v := range vals {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("mv.SliceToStructs() error: value #%d for path %s is not a map: %T", i, path, v)
+		}
+		ev := reflect.New(et)
+		if err := mapToStruct(m, ev.Elem()); err != nil {
+			return fmt.Errorf("mv.SliceToStructs() error: value #%d for path %s: %s", i, path, err.Error())
+		}
+		if isPtr {
+			s = reflect.Append(s, ev)
+		} else {
+			s = reflect.Append(s, ev.Elem())
+		}
+	}
+	sv.Set(s)
+	return nil
+}
+
+// mapToStruct loads the public fields of the structure 'sv' from 'm'.
+func mapToStruct(m map[string]interface{}, sv reflect.Value) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if f.PkgPath != "" { // not a public field
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		v, ok := m[name]
+		if !ok {
+			for k, vv := range m {
+				if strings.EqualFold(k, name) {
+					v, ok = vv, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if err := setValue(sv.Field(i), v); err != nil {
+			return fmt.Errorf("field %s: %s", f.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// setValue loads 'v' into 'rv' converting the value, if necessary.
+func setValue(rv reflect.Value, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return setValue(rv.Elem(), v)
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			break
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		return mapToStruct(m, rv)
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok || !reflect.TypeOf(m).AssignableTo(rv.Type()) {
+			break
+		}
+		rv.Set(reflect.ValueOf(m))
+		return nil
+	case reflect.Slice:
+		a, ok := v.([]interface{})
+		if !ok {
+			if b, ok := v.([]byte); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+				rv.SetBytes(b)
+				return nil
+			}
+			a = []interface{}{v}
+		}
+		s := reflect.MakeSlice(rv.Type(), len(a), len(a))
+		for i, vv := range a {
+			if err := setValue(s.Index(i), vv); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+		return nil
+	case reflect.String:
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			rv.SetString(fmt.Sprint(v))
+			return nil
+		}
+	case reflect.Bool:
+		switch v.(type) {
+		case bool:
+			rv.SetBool(v.(bool))
+			return nil
+		case string:
+			if b, err := strconv.ParseBool(v.(string)); err == nil {
+				rv.SetBool(b)
+				return nil
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s := fmt.Sprint(v)
+		if f, ok := v.(float64); ok {
+			s = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil && !rv.OverflowInt(i) {
+			rv.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s := fmt.Sprint(v)
+		if f, ok := v.(float64); ok {
+			s = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil && !rv.OverflowUint(u) {
+			rv.SetUint(u)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v.(type) {
+		case map[string]interface{}, []interface{}, bool:
+		default:
+			if f, err := strconv.ParseFloat(fmt.Sprint(v), 64); err == nil && !rv.OverflowFloat(f) {
+				rv.SetFloat(f)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("cannot load %T value into %s", v, rv.Type())
+}
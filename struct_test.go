@@ -84,3 +84,64 @@ func TestStructError(t *testing.T) {
 	}
 	fmt.Println("StructError, mverr:", mverr.Error())
 }
+
+func TestSliceToStructs(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	type info struct {
+		Pages int `json:"pages"`
+	}
+	type book struct {
+		Seq     int    `json:"-seq"`
+		Author  string `json:"author"`
+		Title   string
+		Price   float64  `json:"price"`
+		InStock bool     `json:"instock"`
+		Tags    []string `json:"tag"`
+		Info    *info    `json:"info"`
+		Skip    string   `json:"-"`
+	}
+	data := []byte(`<books>
+		<book seq="1"><author>William T. Gaddis</author><title>The Recognitions</title><price>9.99</price><instock>true</instock><tag>novel</tag><tag>classic</tag><info><pages>976</pages></info><skip>x</skip></book>
+		<book seq="2"><author>Austin Tappan Wright</author><title>Islandia</title><price>12</price><instock>false</instock><tag>utopia</tag></book>
+	</books>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var books []book
+	if err := m.SliceToStructs("books.book", &books); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Printf("books: %+v\n", books)
+	if len(books) != 2 {
+		t.Fatal("len(books):", len(books))
+	}
+	b := books[0]
+	if b.Seq != 1 || b.Author != "William T. Gaddis" || b.Title != "The Recognitions" || b.Price != 9.99 ||
+		!b.InStock || len(b.Tags) != 2 || b.Info == nil || b.Info.Pages != 976 || b.Skip != "" {
+		t.Fatalf("books[0]: %+v", b)
+	}
+	b = books[1]
+	if b.Seq != 2 || b.Price != 12 || b.InStock || len(b.Tags) != 1 || b.Tags[0] != "utopia" || b.Info != nil {
+		t.Fatalf("books[1]: %+v", b)
+	}
+
+	var pbooks []*book
+	if err := m.SliceToStructs("books.book", &pbooks); err != nil {
+		t.Fatal(err)
+	}
+	if len(pbooks) != 2 || pbooks[1].Title != "Islandia" {
+		t.Fatal("pbooks:", pbooks)
+	}
+
+	if err := m.SliceToStructs("books.book", books); err == nil {
+		t.Fatal("no error for non-pointer argument")
+	}
+	var bad []struct {
+		Title int
+	}
+	if err := m.SliceToStructs("books.book", &bad); err == nil {
+		t.Fatal("no error loading string into int field")
+	}
+}
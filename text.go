@@ -0,0 +1,67 @@
+// text.go - extract the text content of a Map subtree.
+
+package mxj
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TextForPath returns the text of all the simple element values and "#text" values
+// at or beneath 'path', joined with a space - i.e., the text content of the subtree
+// without the tags. Attribute values are not included; empty values are skipped.
+// Since a Map does not retain the order of the subelements of an element, the text
+// is in the order that mv.Xml() encodes them: the element's "#text" value followed by
+// its subelements in key order, with list members in list order.
+// The 'path' is as for mv.ValuesForPath(); if there is more than one match, the text of
+// each is included. If 'path' does not exist, PathNotExistError is returned.
+func (mv Map) TextForPath(path string) (string, error) {
+	vals, err := mv.ValuesForPath(path)
+	if err != nil {
+		return "", err
+	}
+	if len(vals) == 0 {
+		return "", PathNotExistError
+	}
+	var text []string
+	for _, v := range vals {
+		text = textValues(v, text)
+	}
+	return strings.Join(text, " "), nil
+}
+
+func textValues(v interface{}, text []string) []string {
+	switch v.(type) {
+	case map[string]interface{}:
+		m := v.(map[string]interface{})
+		if t, ok := m["#text"]; ok {
+			text = textValues(t, text)
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			if k == "#text" || k == "#attr" || (lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix)) {
+				continue
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			text = textValues(m[k], text)
+		}
+	case []interface{}:
+		for _, vv := range v.([]interface{}) {
+			text = textValues(vv, text)
+		}
+	case nil:
+	case []byte:
+		if len(v.([]byte)) > 0 {
+			text = append(text, string(v.([]byte)))
+		}
+	default:
+		if s := fmt.Sprintf("%v", v); s != "" {
+			text = append(text, s)
+		}
+	}
+	return text
+}
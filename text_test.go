@@ -0,0 +1,30 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTextForPath(t *testing.T) {
+	fmt.Println("\n------------ text_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc><article id="1">Intro<body><p>one</p><p>two</p><q/></body><author>A. Writer</author></article></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := m.TextForPath("doc.article")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Intro A. Writer one two"; text != want {
+		t.Fatalf("got: %q want: %q", text, want)
+	}
+	if text, _ = m.TextForPath("doc.article.body.p"); text != "one two" {
+		t.Fatalf("doc.article.body.p: %q", text)
+	}
+	if _, err = m.TextForPath("doc.none"); err != PathNotExistError {
+		t.Fatal("err:", err)
+	}
+}
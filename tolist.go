@@ -0,0 +1,79 @@
+// tolist.go - normalize a map of elements with varying keys as a list.
+
+package mxj
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToList returns the subelements of the element at 'path' as a list of Map values, with
+// the key of each subelement set as the 'keyNameField' value of its Map. Thus, for
+// 'path' "users" and 'keyNameField' "-id", {"users":{"u1":{"name":"a"},"u2":{"name":"b"}}}
+// gives
+//	[{"-id":"u1", "name":"a"}, {"-id":"u2", "name":"b"}]
+// which is the same as decoding <users><user id="u1">..</user><user id="u2">..</user></users>
+// the value for "users.user". The subelements are in key order; the members of a list
+// value are each an entry, and a simple element value is the "#text" value of its Map.
+// The attributes and "#text" value of the element at 'path' are not subelements.
+// The returned values are copies, so the Map is not modified.
+// As with mv.UniqueValueForPath(), PathNotExistError or PathNotUniqueError is returned if
+// 'path' does not match exactly one value; error is also returned if the value is not a
+// map[string]interface{} value or a subelement already has a 'keyNameField' key.
+func (mv Map) ToList(path, keyNameField string) ([]Map, error) {
+	v, err := mv.UniqueValueForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ToList: value for path %s is not a map: %T", path, v)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		switch {
+		case k == "#text":
+		case k == "#attr":
+			if _, ok := v.(map[string]interface{}); !ok {
+				keys = append(keys, k)
+			}
+		case lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix):
+		default:
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	list := make([]Map, 0, len(keys))
+	add := func(k string, v interface{}) error {
+		e, ok := copyValue(v).(map[string]interface{})
+		if !ok {
+			e = make(map[string]interface{})
+			if v != nil && v != "" {
+				e["#text"] = v
+			}
+		}
+		if _, ok := e[keyNameField]; ok {
+			return fmt.Errorf("ToList: element %s has a %s key", k, keyNameField)
+		}
+		e[keyNameField] = k
+		list = append(list, Map(e))
+		return nil
+	}
+	for _, k := range keys {
+		if a, ok := m[k].([]interface{}); ok {
+			for _, v := range a {
+				if err := add(k, v); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := add(k, m[k]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,66 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToList(t *testing.T) {
+	fmt.Println("\n------------ tolist_test.go")
+	PrependAttrWithHyphen(true)
+
+	a, err := NewMapXml([]byte(`<users><user id="u1"><name>a</name></user><user id="u2"><name>b</name></user></users>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewMapXml([]byte(`<users count="2"><u2><name>b</name></u2><u1><name>a</name></u1></users>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := a.ValuesForPath("users.user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := b.ToList("users", "-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(want) {
+		t.Fatal("got:", list, "want:", want)
+	}
+	for i := range list {
+		if !Equal(list[i], Map(want[i].(map[string]interface{}))) {
+			t.Fatal(i, "got:", list[i], "want:", want[i])
+		}
+	}
+	list[0]["name"] = "x"
+	if v, _ := b.ValueForPath("users.u1.name"); v != "a" {
+		t.Fatal("Map modified:", b)
+	}
+
+	// simple values and lists
+	m := Map{"doc": map[string]interface{}{"a": "x", "b": []interface{}{"y", map[string]interface{}{"c": "z"}}, "e": ""}}
+	list, err = m.ToList("doc", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantList := []Map{
+		{"key": "a", "#text": "x"},
+		{"key": "b", "#text": "y"},
+		{"key": "b", "c": "z"},
+		{"key": "e"},
+	}
+	if fmt.Sprint(list) != fmt.Sprint(wantList) {
+		t.Fatal("got:", list, "want:", wantList)
+	}
+
+	if _, err = m.ToList("doc.a", "key"); err == nil {
+		t.Fatal("no error for simple value")
+	}
+	if _, err = m.ToList("doc", "c"); err == nil {
+		t.Fatal("no error for existing key field")
+	}
+	if _, err = m.ToList("none", "key"); err != PathNotExistError {
+		t.Fatal("err:", err)
+	}
+}
@@ -0,0 +1,839 @@
+// toml.go - encode a Map as TOML and decode TOML to a Map.
+
+package mxj
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ------------------------------ write TOML -----------------------
+
+// Toml encodes the Map as a TOML document.
+//	- map[string]interface{} values are encoded as tables and lists of maps as arrays of tables.
+//	- Lists of simple values are encoded as arrays; the members must all be strings, integers,
+//	  floats, booleans, time.Time values or arrays - TOML arrays can't have mixed types.
+//	- float64 values are always encoded as TOML floats - e.g., "5.0" - and int, int64, etc.,
+//	  as integers; json.Number values are encoded as their text.
+//	- time.Time values are encoded as RFC 3339 date-times.
+//	- Keys that aren't valid TOML bare keys, such as "#text", are quoted; attribute keys,
+//	  "-attr", are valid bare keys. So an XML decoded Map is encoded as is.
+// Error is returned for values that TOML can't represent - e.g., nil values, lists that
+// mix maps and other values, or mixed-type arrays.
+func (mv Map) Toml() ([]byte, error) {
+	b := new(bytes.Buffer)
+	if err := tomlTable(b, nil, map[string]interface{}(mv)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimPrefix(b.Bytes(), []byte("\n")), nil
+}
+
+// tomlTable writes the key/value pairs of 'm' followed by its tables and arrays of tables;
+// 'path' is the keys of the table 'm'.
+func tomlTable(b *bytes.Buffer, path []string, m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var tables, arrays []string
+	for _, k := range keys {
+		v := m[k]
+		if _, ok := tomlMap(v); ok {
+			tables = append(tables, k)
+			continue
+		}
+		isArray, err := tomlTableArray(v)
+		if err != nil {
+			return fmt.Errorf("Toml: %s: %s", tomlKeyPath(append(path, k)), err.Error())
+		}
+		if isArray {
+			arrays = append(arrays, k)
+			continue
+		}
+		s, err := tomlValue(v)
+		if err != nil {
+			return fmt.Errorf("Toml: %s: %s", tomlKeyPath(append(path, k)), err.Error())
+		}
+		b.WriteString(tomlKey(k) + " = " + s + "\n")
+	}
+
+	for _, k := range tables {
+		p := append(path[:len(path):len(path)], k)
+		b.WriteString("\n[" + tomlKeyPath(p) + "]\n")
+		sub, _ := tomlMap(m[k])
+		if err := tomlTable(b, p, sub); err != nil {
+			return err
+		}
+	}
+	for _, k := range arrays {
+		p := append(path[:len(path):len(path)], k)
+		for _, v := range m[k].([]interface{}) {
+			b.WriteString("\n[[" + tomlKeyPath(p) + "]]\n")
+			sub, _ := tomlMap(v)
+			if err := tomlTable(b, p, sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tomlMap returns 'v' as a map[string]interface{} value, if it is a map.
+func tomlMap(v interface{}) (map[string]interface{}, bool) {
+	switch v.(type) {
+	case map[string]interface{}:
+		return v.(map[string]interface{}), true
+	case Map:
+		return map[string]interface{}(v.(Map)), true
+	}
+	return nil, false
+}
+
+// tomlTableArray reports whether 'v' is a list of maps, encoded as an array of tables.
+func tomlTableArray(v interface{}) (bool, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) == 0 {
+		return false, nil
+	}
+	var n int
+	for _, vv := range a {
+		if _, ok := tomlMap(vv); ok {
+			n++
+		}
+	}
+	switch n {
+	case 0:
+		return false, nil
+	case len(a):
+		return true, nil
+	}
+	return false, errors.New("list mixes maps and other values")
+}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(k string) string {
+	if tomlBareKey.MatchString(k) {
+		return k
+	}
+	return tomlString(k)
+}
+
+func tomlKeyPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, k := range path {
+		keys[i] = tomlKey(k)
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlString returns 's' as a TOML basic string.
+func tomlString(s string) string {
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b = append(b, `\"`...)
+		case '\\':
+			b = append(b, `\\`...)
+		case '\b':
+			b = append(b, `\b`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\f':
+			b = append(b, `\f`...)
+		case '\r':
+			b = append(b, `\r`...)
+		default:
+			if r < 0x20 || r == 0x7f {
+				b = append(b, fmt.Sprintf(`\u%04X`, r)...)
+			} else {
+				b = append(b, string(r)...)
+			}
+		}
+	}
+	return string(append(b, '"'))
+}
+
+func tomlFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// tomlValue returns the TOML text for a key or array member value.
+func tomlValue(v interface{}) (string, error) {
+	switch v.(type) {
+	case nil:
+		return "", errors.New("nil value")
+	case string:
+		return tomlString(v.(string)), nil
+	case []byte:
+		return tomlString(string(v.([]byte))), nil
+	case bool:
+		return strconv.FormatBool(v.(bool)), nil
+	case float64:
+		return tomlFloat(v.(float64)), nil
+	case float32:
+		return tomlFloat(float64(v.(float32))), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		return fmt.Sprintf("%d", v), nil
+	case uint64:
+		if v.(uint64) > math.MaxInt64 {
+			return "", errors.New("integer out of range")
+		}
+		return strconv.FormatUint(v.(uint64), 10), nil
+	case json.Number:
+		s := string(v.(json.Number))
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return s, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid number: %s", s)
+		}
+		if !strings.ContainsAny(s, ".eE") { // an integer beyond the int64 range
+			return "", errors.New("integer out of range")
+		}
+		return tomlFloat(f), nil
+	case time.Time:
+		return v.(time.Time).Format(time.RFC3339Nano), nil
+	case []interface{}:
+		a := v.([]interface{})
+		vals := make([]string, len(a))
+		var kind string
+		for i, vv := range a {
+			if _, ok := tomlMap(vv); ok {
+				return "", errors.New("list mixes maps and other values")
+			}
+			k := tomlKind(vv)
+			if i == 0 {
+				kind = k
+			} else if k != kind {
+				return "", errors.New("mixed-type array")
+			}
+			s, err := tomlValue(vv)
+			if err != nil {
+				return "", err
+			}
+			vals[i] = s
+		}
+		return "[" + strings.Join(vals, ", ") + "]", nil
+	}
+	// other slices - []string, []int, etc.
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		a := make([]interface{}, rv.Len())
+		for i := range a {
+			a[i] = rv.Index(i).Interface()
+		}
+		return tomlValue(a)
+	}
+	return "", fmt.Errorf("unsupported value type: %T", v)
+}
+
+// tomlKind is the TOML type of a simple value for the mixed-type array check.
+func tomlKind(v interface{}) string {
+	switch v.(type) {
+	case string, []byte:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32:
+		return "float"
+	case json.Number:
+		if strings.ContainsAny(string(v.(json.Number)), ".eE") {
+			return "float"
+		}
+		return "integer"
+	case time.Time:
+		return "datetime"
+	case []interface{}:
+		return "array"
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return "array"
+	}
+	return "integer"
+}
+
+// ------------------------------ read TOML -----------------------
+
+// NewMapToml decodes a TOML document as a Map; tables are decoded as map[string]interface{}
+// values and arrays and arrays of tables as []interface{} values.
+//	- Integers are decoded as int64 values, floats as float64 values.
+//	- Offset date-times are decoded as time.Time values; local date-times, dates and times
+//	  are decoded as strings - e.g., "1979-05-27T07:32:00" - since they aren't instants.
+// So the value types are those that mv.Toml() encodes.
+func NewMapToml(tomlVal []byte) (Map, error) {
+	p := &tomlParser{s: tomlVal, line: 1, defined: make(map[uintptr]bool)}
+	m, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("NewMapToml: line %d: %s", p.line, err.Error())
+	}
+	return Map(m), nil
+}
+
+// tomlParser is the state for NewMapToml().
+type tomlParser struct {
+	s       []byte
+	i       int
+	line    int
+	defined map[uintptr]bool // the tables that have been defined with a [table] header
+}
+
+func (p *tomlParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.i]
+}
+
+func (p *tomlParser) next() byte {
+	c := p.s[p.i]
+	p.i++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *tomlParser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.s[p.i:], []byte(s))
+}
+
+// skipSpace skips spaces and tabs.
+func (p *tomlParser) skipSpace() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.i++
+	}
+}
+
+// skipComment skips a comment, if any, up to the end of the line.
+func (p *tomlParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for !p.eof() && p.peek() != '\n' {
+		p.i++
+	}
+}
+
+// skipBlank skips white space, newlines and comments.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		switch p.peek() {
+		case '\n':
+			p.next()
+		case '\r':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+// endLine checks that the rest of the line is blank or a comment.
+func (p *tomlParser) endLine() error {
+	p.skipSpace()
+	p.skipComment()
+	if p.hasPrefix("\r\n") {
+		p.i++
+	}
+	if p.eof() {
+		return nil
+	}
+	if p.next() != '\n' {
+		return errors.New("expected end of line")
+	}
+	return nil
+}
+
+func (p *tomlParser) parse() (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	cur := root
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+		var err error
+		if p.peek() == '[' {
+			cur, err = p.header(root)
+		} else {
+			err = p.keyValue(cur)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = p.endLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// header parses a [table] or [[array of tables]] header and returns the table.
+func (p *tomlParser) header(root map[string]interface{}) (map[string]interface{}, error) {
+	p.i++
+	isArray := p.peek() == '['
+	if isArray {
+		p.i++
+	}
+	p.skipSpace()
+	keys, err := p.key()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	end := "]"
+	if isArray {
+		end = "]]"
+	}
+	if !p.hasPrefix(end) {
+		return nil, fmt.Errorf("expected %s", end)
+	}
+	p.i += len(end)
+
+	m := root
+	for _, k := range keys[:len(keys)-1] {
+		if m, err = tomlSubtable(m, k); err != nil {
+			return nil, err
+		}
+	}
+	k := keys[len(keys)-1]
+	v, ok := m[k]
+	if isArray {
+		t := make(map[string]interface{})
+		switch {
+		case !ok:
+			m[k] = []interface{}{t}
+		default:
+			a, ok := v.([]interface{})
+			if isTable, _ := tomlTableArray(v); !ok || !isTable {
+				return nil, fmt.Errorf("key is not an array of tables: %s", k)
+			}
+			m[k] = append(a, t)
+		}
+		return t, nil
+	}
+	var t map[string]interface{}
+	if !ok {
+		t = make(map[string]interface{})
+		m[k] = t
+	} else if t, ok = v.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("key is not a table: %s", k)
+	}
+	id := reflect.ValueOf(t).Pointer()
+	if p.defined[id] {
+		return nil, fmt.Errorf("table defined more than once: %s", strings.Join(keys, "."))
+	}
+	p.defined[id] = true
+	return t, nil
+}
+
+// tomlSubtable returns the table for 'k' in 'm', creating it if needed; for an array of
+// tables, it is the last table.
+func tomlSubtable(m map[string]interface{}, k string) (map[string]interface{}, error) {
+	switch v := m[k].(type) {
+	case nil:
+		t := make(map[string]interface{})
+		m[k] = t
+		return t, nil
+	case map[string]interface{}:
+		return v, nil
+	case []interface{}:
+		if len(v) > 0 {
+			if t, ok := v[len(v)-1].(map[string]interface{}); ok {
+				return t, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("key is not a table: %s", k)
+}
+
+// keyValue parses a key = value pair into 'm'.
+func (p *tomlParser) keyValue(m map[string]interface{}) error {
+	keys, err := p.key()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.peek() != '=' {
+		return errors.New("expected '='")
+	}
+	p.i++
+	p.skipSpace()
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys[:len(keys)-1] {
+		if m, err = tomlSubtable(m, k); err != nil {
+			return err
+		}
+	}
+	k := keys[len(keys)-1]
+	if _, ok := m[k]; ok {
+		return fmt.Errorf("key defined more than once: %s", k)
+	}
+	m[k] = v
+	return nil
+}
+
+// key parses a bare, quoted or dotted key.
+func (p *tomlParser) key() ([]string, error) {
+	var keys []string
+	for {
+		var k string
+		var err error
+		switch p.peek() {
+		case '"':
+			k, err = p.basicString()
+		case '\'':
+			k, err = p.literalString()
+		default:
+			start := p.i
+			for c := p.peek(); c == '_' || c == '-' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9'; c = p.peek() {
+				p.i++
+			}
+			if p.i == start {
+				return nil, errors.New("expected a key")
+			}
+			k = string(p.s[start:p.i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+		p.skipSpace()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.i++
+		p.skipSpace()
+	}
+}
+
+func (p *tomlParser) value() (interface{}, error) {
+	switch c := p.peek(); {
+	case p.hasPrefix(`"""`):
+		return p.multilineBasicString()
+	case c == '"':
+		return p.basicString()
+	case p.hasPrefix("'''"):
+		return p.multilineLiteralString()
+	case c == '\'':
+		return p.literalString()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	case p.hasPrefix("true"):
+		p.i += 4
+		return true, nil
+	case p.hasPrefix("false"):
+		p.i += 5
+		return false, nil
+	}
+	return p.scalar()
+}
+
+func (p *tomlParser) basicString() (string, error) {
+	p.i++ // the opening quote
+	var b []byte
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", errors.New("unterminated string")
+		}
+		c := p.next()
+		switch c {
+		case '"':
+			return string(b), nil
+		case '\\':
+			r, err := p.escape()
+			if err != nil {
+				return "", err
+			}
+			b = append(b, r...)
+		default:
+			b = append(b, c)
+		}
+	}
+}
+
+func (p *tomlParser) multilineBasicString() (string, error) {
+	p.i += 3
+	p.skipNewline() // a newline following the delimiter is trimmed
+	var b []byte
+	for {
+		if p.eof() {
+			return "", errors.New("unterminated string")
+		}
+		if p.hasPrefix(`"""`) {
+			p.i += 3
+			// up to two quotes may precede the closing delimiter
+			for n := 0; n < 2 && p.peek() == '"'; n++ {
+				b = append(b, p.next())
+			}
+			return string(b), nil
+		}
+		c := p.next()
+		if c != '\\' {
+			b = append(b, c)
+			continue
+		}
+		// a line ending backslash trims the white space up to the next text
+		save, line := p.i, p.line
+		p.skipSpace()
+		if p.peek() == '\n' || p.hasPrefix("\r\n") {
+			for c := p.peek(); c == ' ' || c == '\t' || c == '\n' || c == '\r'; c = p.peek() {
+				p.next()
+			}
+			continue
+		}
+		p.i, p.line = save, line
+		r, err := p.escape()
+		if err != nil {
+			return "", err
+		}
+		b = append(b, r...)
+	}
+}
+
+// escape decodes the escape sequence following a backslash.
+func (p *tomlParser) escape() ([]byte, error) {
+	if p.eof() {
+		return nil, errors.New("unterminated string")
+	}
+	switch c := p.next(); c {
+	case 'b':
+		return []byte{'\b'}, nil
+	case 't':
+		return []byte{'\t'}, nil
+	case 'n':
+		return []byte{'\n'}, nil
+	case 'f':
+		return []byte{'\f'}, nil
+	case 'r':
+		return []byte{'\r'}, nil
+	case '"':
+		return []byte{'"'}, nil
+	case '\\':
+		return []byte{'\\'}, nil
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.i+n > len(p.s) {
+			return nil, errors.New("invalid unicode escape")
+		}
+		r, err := strconv.ParseUint(string(p.s[p.i:p.i+n]), 16, 32)
+		if err != nil {
+			return nil, errors.New("invalid unicode escape")
+		}
+		p.i += n
+		return []byte(string(rune(r))), nil
+	default:
+		return nil, fmt.Errorf("invalid escape sequence: \\%c", c)
+	}
+}
+
+func (p *tomlParser) literalString() (string, error) {
+	p.i++
+	start := p.i
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", errors.New("unterminated string")
+		}
+		if p.next() == '\'' {
+			return string(p.s[start : p.i-1]), nil
+		}
+	}
+}
+
+func (p *tomlParser) multilineLiteralString() (string, error) {
+	p.i += 3
+	p.skipNewline()
+	start := p.i
+	for {
+		if p.eof() {
+			return "", errors.New("unterminated string")
+		}
+		if p.hasPrefix("'''") {
+			end := p.i
+			p.i += 3
+			for n := 0; n < 2 && p.peek() == '\''; n++ {
+				p.i++
+				end++
+			}
+			return string(p.s[start:end]), nil
+		}
+		p.next()
+	}
+}
+
+func (p *tomlParser) skipNewline() {
+	if p.hasPrefix("\r\n") {
+		p.i++
+	}
+	if p.peek() == '\n' {
+		p.next()
+	}
+}
+
+func (p *tomlParser) array() ([]interface{}, error) {
+	p.i++
+	a := make([]interface{}, 0)
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.i++
+			return a, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.i++
+		case ']':
+		default:
+			return nil, errors.New("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (map[string]interface{}, error) {
+	p.i++
+	m := make(map[string]interface{})
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.i++
+		return m, nil
+	}
+	for {
+		p.skipSpace()
+		if err := p.keyValue(m); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.i++
+		case '}':
+			p.i++
+			return m, nil
+		default:
+			return nil, errors.New("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+var (
+	tomlDecimal = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	tomlFloatRe = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	tomlDate    = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+)
+
+// scalar parses a number, date-time, inf or nan value.
+func (p *tomlParser) scalar() (interface{}, error) {
+	start := p.i
+	for c := p.peek(); !p.eof() && c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != ',' && c != ']' && c != '}' && c != '#'; c = p.peek() {
+		p.i++
+	}
+	s := string(p.s[start:p.i])
+	// a date-time may have a space rather than 'T' between the date and the time
+	if tomlDate.MatchString(s) && p.peek() == ' ' && p.i+1 < len(p.s) && p.s[p.i+1] >= '0' && p.s[p.i+1] <= '9' {
+		p.i++
+		for c := p.peek(); !p.eof() && c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != ',' && c != ']' && c != '}' && c != '#'; c = p.peek() {
+			p.i++
+		}
+		s = strings.Replace(string(p.s[start:p.i]), " ", "T", 1)
+	}
+	if s == "" {
+		return nil, errors.New("expected a value")
+	}
+
+	switch s {
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'o' || s[1] == 'b') {
+		base := map[byte]int{'x': 16, 'o': 8, 'b': 2}[s[1]]
+		digits := s[2:]
+		if strings.HasPrefix(digits, "_") || strings.HasSuffix(digits, "_") || strings.Contains(digits, "__") {
+			return nil, fmt.Errorf("invalid integer: %s", s)
+		}
+		n, err := strconv.ParseInt(strings.Replace(digits, "_", "", -1), base, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer: %s", s)
+		}
+		return n, nil
+	}
+	if tomlDecimal.MatchString(s) {
+		n, err := strconv.ParseInt(strings.Replace(s, "_", "", -1), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("integer out of range: %s", s)
+		}
+		return n, nil
+	}
+	if tomlFloatRe.MatchString(s) {
+		f, err := strconv.ParseFloat(strings.Replace(s, "_", "", -1), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float: %s", s)
+		}
+		return f, nil
+	}
+	return tomlDateTime(s)
+}
+
+// tomlDateTime parses the date-time, date and time formats.
+func tomlDateTime(s string) (interface{}, error) {
+	u := strings.ToUpper(s)
+	if t, err := time.Parse(time.RFC3339Nano, u); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-01-02", "15:04:05.999999999"} {
+		if _, err := time.Parse(layout, u); err == nil {
+			return u, nil // local date-times have no time zone
+		}
+	}
+	return nil, fmt.Errorf("invalid value: %s", s)
+}
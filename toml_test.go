@@ -0,0 +1,156 @@
+package mxj
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestToml(t *testing.T) {
+	fmt.Println("\n------------ toml_test.go")
+	PrependAttrWithHyphen(true)
+
+	m, err := NewMapXml([]byte(`<config version="2"><title>TOML "test"</title><server host="a"><port>80</port></server><server host="b"><port>81</port></server><db><ports>1</ports><ports>2</ports><opts><on>true</on></opts></db></config>`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := m.Toml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[config]
+-version = 2.0
+title = "TOML \"test\""
+
+[config.db]
+ports = [1.0, 2.0]
+
+[config.db.opts]
+on = true
+
+[[config.server]]
+-host = "a"
+port = 80.0
+
+[[config.server]]
+-host = "b"
+port = 81.0
+`
+	if string(x) != want {
+		t.Fatal("got:\n" + string(x) + "want:\n" + want)
+	}
+
+	// round trip
+	mt, err := NewMapToml(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !EqualExcept(m, mt) {
+		t.Fatal("got:", mt, "want:", m)
+	}
+
+	// attribute and text keys
+	m = Map{"a": map[string]interface{}{"-id": int64(1), "#text": "x"}, "when": time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)}
+	if x, err = m.Toml(); err != nil {
+		t.Fatal(err)
+	}
+	if want = "when = 1979-05-27T07:32:00Z\n\n[a]\n\"#text\" = \"x\"\n-id = 1\n"; string(x) != want {
+		t.Fatal("got:\n" + string(x) + "want:\n" + want)
+	}
+	if mt, err = NewMapToml(x); err != nil {
+		t.Fatal(err)
+	}
+	if !EqualExcept(m, mt) {
+		t.Fatal("got:", mt, "want:", m)
+	}
+
+	for _, bad := range []Map{
+		{"a": nil},
+		{"a": []interface{}{1, "x"}},
+		{"a": []interface{}{map[string]interface{}{}, 1}},
+	} {
+		if _, err = bad.Toml(); err == nil {
+			t.Fatal("no error for:", bad)
+		}
+	}
+}
+
+func TestNewMapToml(t *testing.T) {
+	data := []byte(`# a comment
+title = "example" # trailing
+"quoted key" = 'C:\path'
+multi = """
+Roses \
+   are red\tand "blue"."""
+lit = '''
+raw \n'''
+ints = [ 1_000, 0xff, 0o17, 0b11, ]
+floats = [1.5, -2e3, inf, nan]
+dt = 1979-05-27 07:32:00-08:00
+local = 1979-05-27T07:32:00
+date = 1979-05-27
+point = { x = 1, y.z = 2 }
+a.b.c = 1
+
+[owner]
+name = "Tom"
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+name = "Nail"
+[products.size]
+mm = 5
+`)
+	m, err := NewMapToml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := map[string]interface{}{
+		"title":               "example",
+		"quoted key":          `C:\path`,
+		"multi":               "Roses are red\tand \"blue\".",
+		"lit":                 `raw \n`,
+		"ints[0]":             int64(1000),
+		"ints[1]":             int64(255),
+		"ints[2]":             int64(15),
+		"ints[3]":             int64(3),
+		"floats[1]":           float64(-2000),
+		"local":               "1979-05-27T07:32:00",
+		"date":                "1979-05-27",
+		"point.x":             int64(1),
+		"point.y.z":           int64(2),
+		"a.b.c":               int64(1),
+		"owner.name":          "Tom",
+		"products[1].name":    "Nail",
+		"products[1].size.mm": int64(5),
+	}
+	for path, want := range checks {
+		if v, err := m.ValueForPath(path); err != nil || v != want {
+			t.Fatalf("%s: got: %T %v want: %v", path, v, v, want)
+		}
+	}
+	if v, _ := m.ValueForPath("floats[2]"); !math.IsInf(v.(float64), 1) {
+		t.Fatal("floats[2]:", v)
+	}
+	if v, _ := m.ValueForPath("dt"); !v.(time.Time).Equal(time.Date(1979, 5, 27, 15, 32, 0, 0, time.UTC)) {
+		t.Fatal("dt:", v)
+	}
+
+	for _, bad := range []string{
+		"a = ",
+		"a = 1\na = 2",
+		"[t]\n[t]",
+		"a = 01",
+		"a = \"open",
+		"a = 1 b = 2",
+		"a = [1 2]",
+		"a = -0o17",
+	} {
+		if _, err = NewMapToml([]byte(bad)); err == nil {
+			t.Fatalf("no error for: %q", bad)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// transform.go - apply a declarative list of operations to a Map.
+
+package mxj
+
+import (
+	"fmt"
+)
+
+// TransformOp.Type values.
+const (
+	TransformRename = "rename" // rename the key at Path to To - see RenameKey()
+	TransformDelete = "delete" // remove Path - see Remove()
+	TransformSet    = "set"    // set the value for Path to Value - see SetValueForPath()
+	TransformMove   = "move"   // set the value for To to the value for Path, then remove Path
+	TransformCopy   = "copy"   // set the value for To to a copy of the value for Path
+)
+
+// TransformOp is an operation applied by mv.Transform(). The JSON tags allow
+// a list of operations to be decoded from a configuration file.
+//	Type  - TransformRename, TransformDelete, TransformSet, TransformMove or TransformCopy.
+//	Path  - the dot-separated path of the key operated on.
+//	To    - the new key name for TransformRename; the destination path for
+//	        TransformMove and TransformCopy.
+//	Value - the value for TransformSet.
+type TransformOp struct {
+	Type  string      `json:"type"`
+	Path  string      `json:"path"`
+	To    string      `json:"to,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Transform applies 'ops' to the Map in sequence. It stops at the first operation
+// that fails and returns its error; the preceding operations are not undone.
+func (mv Map) Transform(ops []TransformOp) error {
+	for i, op := range ops {
+		if err := mv.transform(op); err != nil {
+			return fmt.Errorf("Transform: op #%d %s %s: %s", i, op.Type, op.Path, err.Error())
+		}
+	}
+	return nil
+}
+
+func (mv Map) transform(op TransformOp) error {
+	switch op.Type {
+	case TransformRename:
+		return mv.RenameKey(op.Path, op.To)
+	case TransformDelete:
+		if ok, err := mv.Exists(op.Path); err != nil {
+			return err
+		} else if !ok {
+			return PathNotExistError
+		}
+		return mv.Remove(op.Path)
+	case TransformSet:
+		return mv.SetValueForPath(op.Value, op.Path)
+	case TransformMove, TransformCopy:
+		if op.To == "" {
+			return fmt.Errorf("no destination path")
+		}
+		v, err := mv.ValueForPath(op.Path)
+		if err != nil {
+			return err
+		}
+		if op.Type == TransformCopy {
+			return mv.SetValueForPath(copyValue(v), op.To)
+		}
+		if err = mv.SetValueForPath(v, op.To); err != nil {
+			return err
+		}
+		return mv.Remove(op.Path)
+	}
+	return fmt.Errorf("unknown operation type")
+}
@@ -0,0 +1,61 @@
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	fmt.Println("\n------------ transform_test.go")
+	m, err := NewMapXml([]byte(`<doc><author>William Gaddis</author><info><pages>976</pages><year>1955</year></info><ref>x</ref></doc>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := []byte(`[
+		{"type": "rename", "path": "doc.author", "to": "writer"},
+		{"type": "delete", "path": "doc.ref"},
+		{"type": "set", "path": "doc.info.isbn", "value": "1564781259"},
+		{"type": "move", "path": "doc.info.year", "to": "doc.year"},
+		{"type": "copy", "path": "doc.info", "to": "doc.backup"}
+	]`)
+	var ops []TransformOp
+	if err = json.Unmarshal(config, &ops); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Transform(ops); err != nil {
+		t.Fatal(err)
+	}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc><backup><isbn>1564781259</isbn><pages>976</pages></backup><info><isbn>1564781259</isbn><pages>976</pages></info><writer>William Gaddis</writer><year>1955</year></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	// the copy is independent
+	if err = m.SetValueForPath("0", "doc.backup.pages"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.info.pages"); v.(string) != "976" {
+		t.Fatal("doc.info.pages:", v)
+	}
+
+	err = m.Transform([]TransformOp{
+		{Type: TransformDelete, Path: "doc.writer"},
+		{Type: TransformDelete, Path: "doc.none"},
+		{Type: TransformDelete, Path: "doc.year"},
+	})
+	if err == nil {
+		t.Fatal("no error for bad path")
+	}
+	if ok, _ := m.Exists("doc.year"); !ok {
+		t.Fatal("op applied after error")
+	}
+	if err = m.Transform([]TransformOp{{Type: "squash", Path: "doc"}}); err == nil {
+		t.Fatal("no error for unknown type")
+	}
+}
@@ -0,0 +1,119 @@
+// walk.go - visit all the nodes of a Map with access to their parents.
+
+package mxj
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Node is a Map value visited by mv.WalkNodes().
+type Node struct {
+	Path   string      // dot-notation path of the node with list members subscripted - "a.b[1].c"
+	Key    string      // the Map key of the node; for list members, the key of the list
+	Index  int         // the index of a list member, or -1
+	Value  interface{} // the value of the node when it was visited
+	Parent *Node       // the node of the enclosing map[string]interface{}; 'nil' for top level keys
+	m      map[string]interface{}
+	list   []interface{}
+}
+
+// deletedNode marks list members removed by Node.Delete().
+type deletedNode struct{}
+
+// Replace sets the value of the node in the Map to 'v'. Any subelements of 'v' are
+// visited, rather than those of the node's original value.
+func (n Node) Replace(v interface{}) {
+	if n.Index < 0 {
+		n.m[n.Key] = v
+		return
+	}
+	n.list[n.Index] = v
+}
+
+// Delete removes the node from the Map; its subelements are not visited. If all
+// members of a list are deleted, the key of the list is removed.
+func (n Node) Delete() {
+	if n.Index < 0 {
+		delete(n.m, n.Key)
+		return
+	}
+	n.list[n.Index] = deletedNode{}
+}
+
+// WalkNodes calls 'fn' for each node of the Map - each key:value pair and each member
+// of a list value - with the Node's parent, so that the Map can be restructured as it is
+// walked: e.g., a child can be hoisted to its grandparent by setting it in the
+// Node.Parent.Value map and deleting it. Nodes are visited parents first, with the keys of
+// a map alphabetized. Keys added to a map during the walk are not visited.
+func (mv Map) WalkNodes(fn func(node Node)) {
+	walkMap(map[string]interface{}(mv), nil, "", fn)
+}
+
+func walkMap(m map[string]interface{}, parent *Node, path string, fn func(Node)) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, ok := m[k]
+		if !ok { // deleted while walking
+			continue
+		}
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if list, ok := v.([]interface{}); ok {
+			// an empty list is only removed if its members were deleted
+			if l := walkList(list, k, parent, p, fn); len(l) == 0 && len(list) > 0 {
+				delete(m, k)
+			} else {
+				m[k] = l
+			}
+			continue
+		}
+		n := Node{Path: p, Key: k, Index: -1, Value: v, Parent: parent, m: m}
+		fn(n)
+		if v, ok = m[k]; ok {
+			walkValue(v, &n, p, fn)
+		}
+	}
+}
+
+// walkList visits the members of the list value for 'key' and returns the list
+// without the deleted members.
+func walkList(list []interface{}, key string, parent *Node, path string, fn func(Node)) []interface{} {
+	for i, v := range list {
+		p := path + "[" + strconv.Itoa(i) + "]"
+		n := Node{Path: p, Key: key, Index: i, Value: v, Parent: parent, list: list}
+		if l, ok := v.([]interface{}); ok { // a JSON list of lists
+			list[i] = walkList(l, key, parent, p, fn)
+			continue
+		}
+		fn(n)
+		if _, ok := list[i].(deletedNode); !ok {
+			walkValue(list[i], &n, p, fn)
+		}
+	}
+
+	n := 0
+	for _, v := range list {
+		if _, ok := v.(deletedNode); !ok {
+			list[n] = v
+			n++
+		}
+	}
+	return list[:n]
+}
+
+func walkValue(v interface{}, n *Node, path string, fn func(Node)) {
+	if m, ok := v.(map[string]interface{}); ok {
+		// Parent.Value is the map that is walked, even if the node was replaced
+		pn := *n
+		pn.Value = m
+		walkMap(m, &pn, path, fn)
+	}
+}
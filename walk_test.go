@@ -0,0 +1,68 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWalkNodes(t *testing.T) {
+	fmt.Println("\n------------ walk_test.go")
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc><item id="1"><name>a</name></item><item id="2"><name>b</name></item><meta><ts>now</ts></meta></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	m.WalkNodes(func(n Node) {
+		paths = append(paths, n.Path)
+	})
+	want := []string{"doc", "doc.item[0]", "doc.item[0].-id", "doc.item[0].name",
+		"doc.item[1]", "doc.item[1].-id", "doc.item[1].name", "doc.meta", "doc.meta.ts"}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Fatal("got:", paths, "want:", want)
+	}
+
+	// hoist meta.ts to doc, replace names, delete the first item
+	m.WalkNodes(func(n Node) {
+		switch {
+		case n.Key == "ts":
+			n.Parent.Parent.Value.(map[string]interface{})["ts"] = n.Value
+			n.Parent.Delete()
+		case n.Key == "name":
+			n.Replace(n.Value.(string) + n.Value.(string))
+		case n.Key == "-id" && n.Value == "1":
+			n.Parent.Delete()
+		}
+	})
+	x, _ := m.Xml()
+	if want := `<doc><item id="2"><name>bb</name></item><ts>now</ts></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if n := len(m["doc"].(map[string]interface{})["item"].([]interface{})); n != 1 {
+		t.Fatal("items:", n)
+	}
+
+	// deleting all members removes the list
+	m = Map{"a": []interface{}{1, 2}, "b": []interface{}{[]interface{}{3}}}
+	m.WalkNodes(func(n Node) {
+		if n.Key == "a" || n.Path == "b[0][0]" {
+			n.Delete()
+		}
+	})
+	if _, ok := m["a"]; ok {
+		t.Fatal("a not removed:", m)
+	}
+	if fmt.Sprint(m["b"]) != "[[]]" {
+		t.Fatal("b:", m["b"])
+	}
+
+	// walking doesn't modify the Map
+	m = Map{"a": []interface{}{}, "b": map[string]interface{}{"c": []interface{}{}}}
+	m.WalkNodes(func(n Node) {})
+	if fmt.Sprint(m) != "map[a:[] b:map[c:[]]]" {
+		t.Fatal("modified:", m)
+	}
+}
@@ -7,7 +7,8 @@
 package x2j
 
 import (
-	. "github.com/karthick18/mxj"
+	"bytes"
+	. "github.com/karthick18/mxj/v2"
 	"io"
 )
 
@@ -63,6 +64,141 @@ func XmlReaderToJsonWriter(xmlReader io.Reader, jsonWriter io.Writer, safeEncodi
 	return xraw, jraw, jerr
 }
 
+// XmlReaderToJsonWriterResilient is XmlReaderToJsonWriter() for a stream of XML messages,
+// which are written on 'jsonWriter' as lines of JSON until io.EOF is reached.
+// If a message can't be decoded, 'onError' is called with the error. If it returns 'true',
+// the rest of the message - up to the next end tag of its root element - is skipped and
+// the next message is handled; if it returns 'false', the error is returned.
+// Errors writing on 'jsonWriter' are always returned; io.EOF is not.
+func XmlReaderToJsonWriterResilient(xmlReader io.Reader, jsonWriter io.Writer, onError func(err error) bool, safeEncoding ...bool) error {
+	for {
+		m, xraw, err := NewMapXmlReaderRaw(xmlReader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if !onError(err) {
+				return err
+			}
+			if err = skipXmlMsg(xmlReader, xraw); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err = m.JsonWriterRaw(jsonWriter, safeEncoding...); err != nil {
+			return err
+		}
+		if _, err = jsonWriter.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+}
+
+// skipXmlMsg reads 'xmlReader' past the end tag of the root element of the partial
+// message 'xraw'. The tags in 'xraw' are scanned first - the decoder may have read the
+// end tag already - then, if the root element is still open, the tags that follow in
+// 'xmlReader'; start and end tags with the root element's name are counted, so that
+// nested elements with the same name are skipped, too. If 'xraw' has no start tag,
+// nothing is skipped.
+func skipXmlMsg(xmlReader io.Reader, xraw []byte) error {
+	s := &tagScanner{r: io.MultiReader(bytes.NewReader(xraw), xmlReader)}
+	var name string
+	var depth int
+	for {
+		if name == "" && s.n >= len(xraw) {
+			return nil
+		}
+		tag, err := s.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(tag) == 0 || tag[0] == '!' || tag[0] == '?':
+			// comment, CDATA section, directive or process instruction
+		case tag[0] == '/':
+			if name != "" && tagName(tag[1:]) == name {
+				if depth--; depth == 0 {
+					return nil
+				}
+			}
+		default:
+			n := tagName(tag)
+			if name == "" {
+				name = n
+			}
+			if n == name && tag[len(tag)-1] != '/' {
+				depth++
+			} else if depth == 0 {
+				return nil // the root element is an empty element
+			}
+		}
+	}
+}
+
+// tagScanner reads the tags - the text between '<' and '>' - from an io.Reader;
+// the reader is read a byte at a time, as NewMapXmlReaderRaw() does, so that the
+// next message isn't consumed.
+type tagScanner struct {
+	r io.Reader
+	b [1]byte
+	n int // bytes read
+}
+
+func (s *tagScanner) readByte() (byte, error) {
+	if _, err := io.ReadFull(s.r, s.b[:]); err != nil {
+		return 0, err
+	}
+	s.n++
+	return s.b[0], nil
+}
+
+// next returns the next tag, without the enclosing '<' and '>'.
+func (s *tagScanner) next() ([]byte, error) {
+	for {
+		c, err := s.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if c == '<' {
+			break
+		}
+	}
+	var tag []byte
+	var quote byte
+	for {
+		c, err := s.readByte()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '>':
+			// comments and CDATA sections end with "-->" and "]]>"
+			if bytes.HasPrefix(tag, []byte("!--")) && !(len(tag) >= 5 && bytes.HasSuffix(tag, []byte("--"))) ||
+				bytes.HasPrefix(tag, []byte("![CDATA[")) && !(len(tag) >= 10 && bytes.HasSuffix(tag, []byte("]]"))) {
+				break
+			}
+			return tag, nil
+		case (c == '"' || c == '\'') && len(tag) > 0 && tag[0] != '!':
+			quote = c
+		}
+		tag = append(tag, c)
+	}
+}
+
+// tagName returns the name - with any name space prefix - of the tag text.
+func tagName(tag []byte) string {
+	if i := bytes.IndexAny(tag, " \t\r\n/"); i >= 0 {
+		tag = tag[:i]
+	}
+	return string(tag)
+}
+
 // XML wrappers for Map methods implementing tag path and value functions.
 
 // Wrap PathsForKey for XML.
@@ -0,0 +1,41 @@
+package x2j
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXmlReaderToJsonWriterResilient(t *testing.T) {
+	data := `<msg><a>1</msg><msg><a>2</a></msg><msg><a>3</a></msg>` +
+		`<msg><msg>4</msg><b x="</msg>">5</b>` + // truncated, with a nested msg element
+		`</c><!-- </msg> --></msg>` +
+		`<msg><a>6</a></msg>`
+	var out bytes.Buffer
+	var errs int
+	err := XmlReaderToJsonWriterResilient(bytes.NewReader([]byte(data)), &out, func(err error) bool {
+		errs++
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"msg\":{\"a\":\"2\"}}\n{\"msg\":{\"a\":\"3\"}}\n{\"msg\":{\"a\":\"6\"}}\n"
+	if out.String() != want || errs != 2 {
+		t.Fatal("errs:", errs, "got:", out.String(), "want:", want)
+	}
+
+	// an empty root element
+	out.Reset()
+	data = `<msg x=/><msg><a>2</a></msg>`
+	if err = XmlReaderToJsonWriterResilient(bytes.NewReader([]byte(data)), &out, func(err error) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	if want = "{\"msg\":{\"a\":\"2\"}}\n"; out.String() != want {
+		t.Fatal("got:", out.String(), "want:", want)
+	}
+
+	out.Reset()
+	if err = XmlReaderToJsonWriterResilient(bytes.NewReader([]byte(`<msg><a>1</msg>`)), &out, func(err error) bool { return false }); err == nil {
+		t.Fatal("no error")
+	}
+}
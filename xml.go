@@ -58,6 +58,9 @@ var XmlCharsetReader func(charset string, input io.Reader) (io.Reader, error)
 //	   3. If CoerceKeysToLower() has been called, then all key values will be lower case.
 //	   4. If CoerceKeysToSnakeCase() has been called, then all key values will be converted to snake case.
 //	   5. If DisableTrimWhiteSpace(b bool) has been called, then all values will be trimmed or not. 'true' by default.
+//	   6. If DecodeAttrsAsMap() has been called, then attributes are decoded as a map value for the "#attr" key.
+//	   7. Name space declarations are decoded as "-xmlns" and "-xmlns:<prefix>" attribute keys, so they are
+//	      re-encoded by mv.Xml(); use PreserveNamespacePrefixes() to keep the prefixes of element and attribute names.
 func NewMapXml(xmlVal []byte, cast ...bool) (Map, error) {
 	var r bool
 	if len(cast) == 1 {
@@ -66,6 +69,54 @@ func NewMapXml(xmlVal []byte, cast ...bool) (Map, error) {
 	return xmlToMap(xmlVal, r)
 }
 
+// NewMapXmlUnwrapRoot decodes 'xmlVal' as with NewMapXml() and returns the value of the
+// root element as the Map, along with the root tag. If the root element is a simple
+// element, the Map is map["#text":<value>]; if it is an empty element, the Map is empty.
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+func NewMapXmlUnwrapRoot(xmlVal []byte, cast ...bool) (Map, string, error) {
+	m, err := NewMapXml(xmlVal, cast...)
+	if err != nil {
+		return m, "", err
+	}
+	for k, v := range m {
+		switch v.(type) {
+		case map[string]interface{}:
+			return v.(map[string]interface{}), k, nil
+		case string:
+			if v.(string) == "" {
+				return make(map[string]interface{}, 0), k, nil
+			}
+		}
+		return map[string]interface{}{"#text": v}, k, nil
+	}
+	return m, "", nil
+}
+
+var PathNotUniqueError = errors.New("Path matches more than one value")
+
+// XmlPathValue decodes 'xmlVal' as with NewMapXml() and returns the single value for 'path'.
+// The 'path' is as for mv.ValuesForPath() - it can contain wildcards and indexed array
+// references; however, it must match exactly one value. If there is no match, PathNotExistError
+// is returned; if there is more than one match, PathNotUniqueError is returned.
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+func XmlPathValue(xmlVal []byte, path string, cast ...bool) (interface{}, error) {
+	m, err := NewMapXml(xmlVal, cast...)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := m.ValuesForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	switch len(vals) {
+	case 0:
+		return nil, PathNotExistError
+	case 1:
+		return vals[0], nil
+	}
+	return nil, PathNotUniqueError
+}
+
 // Get next XML doc from an io.Reader as a Map value.  Returns Map value.
 //	NOTES:
 //	   1. Declarations, directives, process instructions and comments are NOT parsed.
@@ -85,6 +136,7 @@ func NewMapXmlReader(xmlReader io.Reader, cast ...bool) (Map, error) {
 	if _, ok := xmlReader.(io.ByteReader); !ok {
 		xmlReader = myByteReader(xmlReader) // see code at EOF
 	}
+	xmlReader = limitXmlReader(xmlReader) // per SetXmlReaderMaxMsgSize
 
 	// build the map
 	return xmlReaderToMap(xmlReader, r)
@@ -111,7 +163,7 @@ func NewMapXmlReaderRaw(xmlReader io.Reader, cast ...bool) (Map, []byte, error)
 	// create TeeReader so we can retrieve raw XML
 	buf := make([]byte, 0)
 	wb := bytes.NewBuffer(buf)
-	trdr := myTeeReader(xmlReader, wb) // see code at EOF
+	trdr := limitXmlReader(myTeeReader(xmlReader, wb)) // see code at EOF
 
 	m, err := xmlReaderToMap(trdr, r)
 
@@ -125,28 +177,155 @@ func NewMapXmlReaderRaw(xmlReader io.Reader, cast ...bool) (Map, []byte, error)
 	return m, b, nil
 }
 
+// Get next XML doc from an io.Reader as a Map value, reporting progress while it is decoded.
+// The 'progress' function is called every 'n' elements with the number of bytes read from
+// 'xmlReader' - per xml.Decoder.InputOffset() - and the number of elements parsed so far;
+// it is called a final time when the XML doc has been decoded, if the count is not a multiple
+// of 'n'. If 'n' < 1, 'progress' is only called when the XML doc has been decoded.
+// This is intended for providing feedback, such as a progress bar, while decoding very
+// large XML docs. Otherwise, it is the same as NewMapXmlReader().
+func NewMapXmlReaderProgress(xmlReader io.Reader, n int, progress func(bytesRead int64, elements int), cast ...bool) (Map, error) {
+	var r bool
+	if len(cast) == 1 {
+		r = cast[0]
+	}
+	if _, ok := xmlReader.(io.ByteReader); !ok {
+		xmlReader = myByteReader(xmlReader) // see code at EOF
+	}
+	xmlReader = limitXmlReader(xmlReader) // per SetXmlReaderMaxMsgSize
+
+	xmlReader, pos := sourcePositions(xmlReader)
+	p := xml.NewDecoder(xmlReader)
+	if CustomDecoder != nil {
+		useCustomDecoder(p)
+	} else {
+		p.CharsetReader = XmlCharsetReader
+	}
+	prog := &xmlProgress{every: n, fn: progress}
+	m, err := xmlToMapParser("", nil, p, r, prog, false, pos)
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil && (n < 1 || prog.elements%n != 0) {
+		progress(p.InputOffset(), prog.elements)
+	}
+	return m, nil
+}
+
+// NewMapXmlMaxArray decodes 'xmlVal' as with NewMapXml(), but keeps at most 'maxPerKey'
+// elements of any set of sibling elements with the same key; the rest are discarded as
+// they are parsed, so the resulting Map is a sample of very large lists whose size is
+// bounded while it is decoded - rather than truncating the lists afterwards.
+// If 'maxPerKey' < 1, all elements are kept.
+//	If the optional argument 'cast' is 'true', then values will be converted to boolean or float64 if possible.
+func NewMapXmlMaxArray(xmlVal []byte, maxPerKey int, cast ...bool) (Map, error) {
+	var r bool
+	if len(cast) == 1 {
+		r = cast[0]
+	}
+	if decodeRawAttrValues {
+		xmlVal = rawAttrLineEndings(xmlVal)
+	}
+	b, pos := sourcePositions(bytes.NewReader(xmlVal))
+	p := xml.NewDecoder(b)
+	if CustomDecoder != nil {
+		useCustomDecoder(p)
+	} else {
+		p.CharsetReader = XmlCharsetReader
+	}
+	return xmlToMapParser("", nil, p, r, &xmlProgress{maxArray: maxPerKey}, false, pos)
+}
+
+// xmlProgress is the state for NewMapXmlReaderProgress() and NewMapXmlMaxArray().
+type xmlProgress struct {
+	every    int
+	fn       func(int64, int)
+	elements int
+	maxArray int // per NewMapXmlMaxArray
+}
+
+// full reports whether the 'na' sub-elements already have maxArray 'name' elements.
+func (x *xmlProgress) full(na map[string]interface{}, name string) bool {
+	if x.maxArray < 1 {
+		return false
+	}
+	if lowerCase || LowercaseKeys {
+		name = strings.ToLower(name)
+	}
+	if snakeCaseKeys {
+		name = strings.Replace(name, "-", "_", -1)
+	}
+	v, ok := na[name]
+	if !ok {
+		return false
+	}
+	if a, ok := v.([]interface{}); ok {
+		return len(a) >= x.maxArray
+	}
+	return x.maxArray == 1
+}
+
+// skipElement consumes the tokens of the element whose xml.StartElement was just read,
+// through its xml.EndElement.
+func skipElement(p *xml.Decoder) error {
+	for depth := 1; depth > 0; {
+		var t xml.Token
+		var err error
+		if preserveNsPrefix {
+			t, err = p.RawToken()
+		} else {
+			t, err = p.Token()
+		}
+		if err != nil {
+			if err == XmlMsgTooLargeError || err == io.EOF {
+				return err
+			}
+			return errors.New("xml.Decoder.Token() - " + err.Error())
+		}
+		switch t.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
+
+// element counts a parsed element and calls the progress function every 'every' elements.
+func (x *xmlProgress) element(p *xml.Decoder) {
+	x.elements++
+	if x.fn != nil && x.every > 0 && x.elements%x.every == 0 {
+		x.fn(p.InputOffset(), x.elements)
+	}
+}
+
 // xmlReaderToMap() - parse a XML io.Reader to a map[string]interface{} value
 func xmlReaderToMap(rdr io.Reader, r bool) (map[string]interface{}, error) {
 	// parse the Reader
+	rdr, pos := sourcePositions(rdr)
 	p := xml.NewDecoder(rdr)
 	if CustomDecoder != nil {
 		useCustomDecoder(p)
 	} else {
 		p.CharsetReader = XmlCharsetReader
 	}
-	return xmlToMapParser("", nil, p, r)
+	return xmlToMapParser("", nil, p, r, nil, false, pos)
 }
 
 // xmlToMap - convert a XML doc into map[string]interface{} value
 func xmlToMap(doc []byte, r bool) (map[string]interface{}, error) {
-	b := bytes.NewReader(doc)
+	if decodeRawAttrValues {
+		doc = rawAttrLineEndings(doc)
+	}
+	b, pos := sourcePositions(bytes.NewReader(doc))
 	p := xml.NewDecoder(b)
 	if CustomDecoder != nil {
 		useCustomDecoder(p)
 	} else {
 		p.CharsetReader = XmlCharsetReader
 	}
-	return xmlToMapParser("", nil, p, r)
+	return xmlToMapParser("", nil, p, r, nil, false, pos)
 }
 
 // ===================================== where the work happens =============================
@@ -236,6 +415,11 @@ func CoerceKeysToLower(b ...bool) {
 	}
 }
 
+// LowercaseKeys, if 'true', causes element and attribute names to be decoded as lower
+// case keys by the same functions as CoerceKeysToLower(). It is an alternative to calling
+// CoerceKeysToLower(true); the attribute prefix is not changed - see SetAttrPrefix().
+var LowercaseKeys bool
+
 // disableTrimWhiteSpace sets if the white space should be removed or not
 var disableTrimWhiteSpace bool
 var trimRunes = "\t\r\b\n "
@@ -263,12 +447,27 @@ func DisableTrimWhiteSpace(b ...bool) {
 var attrPrefix string = `-` // the default
 var lenAttrPrefix int = 1   // the default
 
-// SetAttrPrefix changes the default, "-", to the specified value, s.
-// SetAttrPrefix("") is the same as PrependAttrWithHyphen(false).
+// SetAttrPrefix changes the default, "-", to the specified value, s - e.g., "@" for
+// BadgerFish style keys. The prefix is used both to decode attributes, NewMapXml(), etc.,
+// and to identify the attribute keys when encoding, mv.Xml(), etc.; so XML decoded with
+// a prefix round-trips with it, including through JSON.
+// Error is returned, and the prefix is not changed, if 's' is empty - use
+// PrependAttrWithHyphen(false) to decode attributes without a prefix - or if it can't be
+// used in a key path segment - it has '.', '[' or ']' - or starts with '#', as the "#text"
+// and "#attr" keys do.
 // (Not applicable for NewMapXmlSeq(), mv.XmlSeq(), etc.)
-func SetAttrPrefix(s string) {
+func SetAttrPrefix(s string) error {
+	if s == "" || strings.ContainsAny(s, ".[]") || strings.HasPrefix(s, "#") {
+		return fmt.Errorf("invalid attribute prefix: %q", s)
+	}
 	attrPrefix = s
 	lenAttrPrefix = len(attrPrefix)
+	return nil
+}
+
+// AttrPrefix returns the current attribute prefix - see SetAttrPrefix().
+func AttrPrefix() string {
+	return attrPrefix
 }
 
 // 18jan17: Allows user to specify if the map keys should be in snake case instead
@@ -339,11 +538,116 @@ func DecodeSimpleValuesAsMap(b ...bool) {
 	}
 }
 
+// decode attributes as map["#attr"]map[<attr_label>]<value>
+var decodeAttrsAsMap bool
+
+// DecodeAttrsAsMap causes the attributes of an element to be decoded as a single map
+// value for the "#attr" key - map["#attr"]map[<attr_label>]<value> - rather than as
+// key:value pairs with the attribute prefix prepended to the attribute label. Thus
+// attributes and subelements with the same label cannot collide, even if the keys
+// are later normalized. If called with no argument, the decoding is toggled on/off.
+//	<elem type="attr"><type>elem</type></elem>
+// decodes as:
+//	map["elem"]map["#attr"]map["type":"attr"], "type":"elem"]
+// mv.Xml(), mv.XmlIndent(), etc., encode the "#attr" map value as the element's attributes.
+// NOTE: the attribute prefix is not used; and it does not apply to NewMapXmlSeq... functions.
+func DecodeAttrsAsMap(b ...bool) {
+	if len(b) == 0 {
+		decodeAttrsAsMap = !decodeAttrsAsMap
+	} else if len(b) == 1 {
+		decodeAttrsAsMap = b[0]
+	}
+}
+
+// key for the text of elements with subelements - see SetMixedTextKey.
+var mixedTextKey string
+
+// SetMixedTextKey changes how NewMapXml... functions decode the text of an element that
+// also has subelements. By default the text is the value of the "#text" key alongside
+// the subelement keys. If 'key' is not "", the text is decoded as the value of a 'key'
+// subelement, instead, so every element with subelements is a map of named children:
+//	<elem>text<sub>value</sub></elem>
+// decodes with SetMixedTextKey("text") as:
+//	map["elem"]map["text":"text", "sub":"value"]
+// The text of simple elements with attributes is still the value of the "#text" key;
+// as it is if there is already a 'key' subelement. SetMixedTextKey("") restores the
+// default. NOTE: on encoding, the 'key' value is encoded as a subelement, not as text.
+// (Not applicable to NewMapXmlSeq... functions.)
+func SetMixedTextKey(key string) {
+	mixedTextKey = key
+}
+
+// nestMixedText - per SetMixedTextKey, move the "#text" value to the mixedTextKey subelement
+// if 'na' has subelements.
+func nestMixedText(na map[string]interface{}) {
+	t, ok := na["#text"]
+	if !ok {
+		return
+	}
+	if _, ok := na[mixedTextKey]; ok {
+		return
+	}
+	for k := range na {
+		switch {
+		case k == "#text", k == "#attr":
+			continue
+		case lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix):
+			continue
+		}
+		na[mixedTextKey] = t
+		delete(na, "#text")
+		return
+	}
+}
+
+// preserve name space prefixes in element and attribute keys
+var preserveNsPrefix bool
+
+// PreserveNamespacePrefixes causes NewMapXml... functions to preserve name space
+// syntax in keys, as NewMapXmlSeq... functions do, rather than decoding only the local
+// name of elements and attributes. If called with no argument, the decoding is toggled on/off.
+//	<ns:key xmlns:ns="http://myns.com/ns">something</ns:key>
+// decodes as:
+//	map["ns:key"]map["-xmlns:ns":"http://myns.com/ns", "#text":"something"]
+// Attribute keys keep their prefixes in the same way - e.g., xlink:href="#a" decodes as
+// "-xlink:href":"#a" and is encoded as xlink:href="#a"; without the setting it decodes
+// as "-href":"#a". (The "xmlns:prefix" declarations always keep the prefix.)
+// NOTE: the name space prefixes are not resolved; use ValuesForPathNS to query such Map
+// values by name space URI, or mv.DropNamespaces() to remove them.
+func PreserveNamespacePrefixes(b ...bool) {
+	if len(b) == 0 {
+		preserveNsPrefix = !preserveNsPrefix
+	} else if len(b) == 1 {
+		preserveNsPrefix = b[0]
+	}
+}
+
+// xmlName returns the key for an element or attribute name; the name space
+// prefix is included per PreserveNamespacePrefixes.
+func xmlName(n xml.Name) string {
+	if preserveNsPrefix && len(n.Space) > 0 {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+// xmlAttrName is xmlName for attributes; name space declarations always keep
+// the "xmlns:" prefix, so they are not confused with other attributes.
+func xmlAttrName(n xml.Name) string {
+	if n.Space == "xmlns" {
+		return "xmlns:" + n.Local
+	}
+	return xmlName(n)
+}
+
 // xmlToMapParser (2015.11.12) - load a 'clean' XML doc into a map[string]interface{} directly.
 // A refactoring of xmlToTreeParser(), markDuplicate() and treeToMap() - here, all-in-one.
 // We've removed the intermediate *node tree with the allocation and subsequent rescanning.
-func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[string]interface{}, error) {
-	if lowerCase {
+// If 'prog' is not 'nil', progress is reported as elements are parsed.
+// If 'space' is 'true', xml:space="preserve" is in scope - see xmlSpacePreserve().
+// If 'pos' is not 'nil', the element source positions or bytes are recorded - see DecodeSourcePositions.
+func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool, prog *xmlProgress, space bool, pos *xmlPositions) (map[string]interface{}, error) {
+	if lowerCase || LowercaseKeys {
 		skey = strings.ToLower(skey)
 	}
 	if snakeCaseKeys {
@@ -354,40 +658,71 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 	// Unless 'skey' is a simple element w/o attributes, in which case the xml.CharData value is the value.
 	var n, na map[string]interface{}
 	var seq int // for includeTagSeqNum
+	// per xml:space="preserve", the text is only white space; it's dropped if there are subelements
+	var wsText, hasSubelem bool
+	// the text of consecutive xml.CharData tokens - e.g., text and CDATA sections - is merged
+	var chars string
 
 	// Allocate maps and load attributes, if any.
 	// NOTE: on entry from NewMapXml(), etc., skey=="", and we fall through
 	//       to get StartElement then recurse with skey==xml.StartElement.Name.Local
 	//       where we begin allocating map[string]interface{} values 'n' and 'na'.
+	if skey == "" && pos != nil {
+		pos.charsetReader(p) // before the xml declaration is read
+	}
 	if skey != "" {
 		n = make(map[string]interface{})  // old n
 		na = make(map[string]interface{}) // old n.nodes
 		if len(a) > 0 {
+			// per DecodeAttrsAsMap, attributes are loaded in 'aa' rather than 'na'
+			var aa map[string]interface{}
+			if decodeAttrsAsMap {
+				aa = make(map[string]interface{}, len(a))
+				na["#attr"] = aa
+			}
 			for _, v := range a {
+				key := xmlAttrName(v.Name)
 				if snakeCaseKeys {
-					v.Name.Local = strings.Replace(v.Name.Local, "-", "_", -1)
+					// as for element keys, including the name space prefix
+					key = strings.Replace(key, "-", "_", -1)
+				}
+				if lowerCase || LowercaseKeys {
+					key = strings.ToLower(key) // preserve the attribute prefix
 				}
-				var key string
-				key = attrPrefix + v.Name.Local
-				if lowerCase {
-					key = strings.ToLower(key)
+				if !decodeAttrsAsMap {
+					key = attrPrefix + key
 				}
 				if xmlEscapeCharsDecoder { // per issue#84
 					v.Value = escapeChars(v.Value)
 				}
+				if decodeAttrsAsMap {
+					aa[key] = cast(v.Value, r, key)
+					continue
+				}
 				na[key] = cast(v.Value, r, key)
 			}
 		}
 	}
 	// Return XMPP <stream:stream> message.
-	if handleXMPPStreamTag && skey == "stream" {
+	if handleXMPPStreamTag && (skey == "stream" || skey == "stream:stream") {
 		n[skey] = na
 		return n, nil
 	}
 
 	for {
-		t, err := p.Token()
+		var t xml.Token
+		var err error
+		off := p.InputOffset() // the start of the token
+		if preserveNsPrefix {
+			// don't translate name space prefixes to URIs
+			t, err = p.RawToken()
+		} else {
+			t, err = p.Token()
+		}
 		if err != nil {
+			if err == XmlMsgTooLargeError {
+				return nil, err
+			}
 			if err != io.EOF {
 				return nil, errors.New("xml.Decoder.Token() - " + err.Error())
 			}
@@ -396,6 +731,9 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 		switch t.(type) {
 		case xml.StartElement:
 			tt := t.(xml.StartElement)
+			if prog != nil {
+				prog.element(p)
+			}
 
 			// First call to xmlToMapParser() doesn't pass xml.StartElement - the map key.
 			// So when the loop is first entered, the first token is the root tag along
@@ -405,15 +743,40 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 			// processing before getting the next token which is the element value,
 			// which is done above.
 			if skey == "" {
-				return xmlToMapParser(tt.Name.Local, tt.Attr, p, r)
+				m, err := xmlToMapParser(xmlName(tt.Name), tt.Attr, p, r, prog, xmlSpacePreserve(tt.Attr, space), pos)
+				if err == nil && pos != nil {
+					end := p.InputOffset()
+					for k, v := range m {
+						m[k] = pos.annotate(v, off, end)
+					}
+				}
+				return m, err
+			}
+
+			// White space between subelements isn't the element's text.
+			if wsText {
+				delete(n, skey)
+				delete(na, "#text")
+				wsText = false
+			}
+			hasSubelem = true
+			chars = ""
+
+			// per NewMapXmlMaxArray, discard the element if there are enough already
+			if prog != nil && prog.full(na, xmlName(tt.Name)) {
+				if err := skipElement(p); err != nil {
+					return nil, err
+				}
+				continue
 			}
 
 			// If not initializing the map, parse the element.
 			// len(nn) == 1, necessarily - it is just an 'n'.
-			nn, err := xmlToMapParser(tt.Name.Local, tt.Attr, p, r)
+			nn, err := xmlToMapParser(xmlName(tt.Name), tt.Attr, p, r, prog, xmlSpacePreserve(tt.Attr, space), pos)
 			if err != nil {
 				return nil, err
 			}
+			end := p.InputOffset() // the end of the element, for DecodeSourceBytes
 
 			// The nn map[string]interface{} value is a na[nn_key] value.
 			// We need to see if nn_key already exists - means we're parsing a list.
@@ -447,6 +810,9 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 					val = v
 				}
 			}
+			if pos != nil {
+				val = pos.annotate(val, off, end)
+			}
 
 			// 'na' holding sub-elements of n.
 			// See if 'key' already exists.
@@ -465,6 +831,20 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 				na[key] = val // save it as a singleton
 			}
 		case xml.EndElement:
+			// RawToken() doesn't check that the element is properly terminated.
+			if preserveNsPrefix && skey != "" {
+				name := xmlName(t.(xml.EndElement).Name)
+				if lowerCase || LowercaseKeys {
+					name = strings.ToLower(name)
+				}
+				if snakeCaseKeys {
+					name = strings.Replace(name, "-", "_", -1)
+				}
+				if skey != name {
+					return nil, fmt.Errorf("element %s not properly terminated, got %s at #%d",
+						skey, name, p.InputOffset())
+				}
+			}
 			// len(n) > 0 if this is a simple element w/o xml.Attrs - see xml.CharData case.
 			if len(n) == 0 {
 				// If len(na)==0 we have an empty element == "";
@@ -483,10 +863,24 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 				}
 				n[skey] = na
 			}
+			if mixedTextKey != "" && len(na) > 1 {
+				nestMixedText(na)
+			}
 			return n, nil
 		case xml.CharData:
+			// text split by CDATA sections, comments, etc., is merged; text following
+			// a subelement is not
+			chars += string(t.(xml.CharData))
 			// clean up possible noise
-			tt := strings.Trim(string(t.(xml.CharData)), trimRunes)
+			tt := strings.Trim(chars, trimRunes)
+			if space && skey != "" {
+				// per xml:space="preserve" keep all white space
+				if raw := chars; len(tt) > 0 {
+					tt, wsText = raw, false
+				} else if _, ok := na["#text"]; len(raw) > 0 && !hasSubelem && (wsText || len(n) == 0 && !ok) {
+					tt, wsText = raw, true
+				}
+			}
 			if xmlEscapeCharsDecoder { // issue#84
 				tt = escapeChars(tt)
 			}
@@ -510,6 +904,24 @@ func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[stri
 	}
 }
 
+// xmlSpacePreserve returns whether xml:space="preserve" is in scope for an element with
+// the attributes 'a'; 'space' is the scope of the parent element. An xml:space="default"
+// attribute restores the normal white space handling.
+func xmlSpacePreserve(a []xml.Attr, space bool) bool {
+	for _, v := range a {
+		if v.Name.Local != "space" || (v.Name.Space != "xml" && v.Name.Space != xmlNamespace) {
+			continue
+		}
+		switch v.Value {
+		case "preserve":
+			return true
+		case "default":
+			return false
+		}
+	}
+	return space
+}
+
 var castNanInf bool
 
 // Cast "Nan", "Inf", "-Inf" XML values to 'float64'.
@@ -541,6 +953,11 @@ func cast(s string, r bool, t string) interface{} {
 		}
 
 		// handle numeric strings ahead of boolean
+		if RecastAsJsonNumber && (castToInt || castToFloat) {
+			if _, err := strconv.ParseFloat(s, 64); err == nil && json.Valid([]byte(s)) {
+				return json.Number(s)
+			}
+		}
 		if castToInt {
 			if f, err := strconv.ParseInt(s, 10, 64); err == nil {
 				return f
@@ -559,7 +976,14 @@ func cast(s string, r bool, t string) interface{} {
 		// ParseBool treats "1"==true & "0"==false, we've already scanned those
 		// values as float64. See if value has 't' or 'f' as initial screen to
 		// minimize calls to ParseBool; also, see if len(s) < 6.
-		if castToBool {
+		if castToBool && castToBoolStrict {
+			switch s {
+			case "true":
+				return true
+			case "false":
+				return false
+			}
+		} else if castToBool {
 			if len(s) > 0 && len(s) < 6 {
 				switch s[:1] {
 				case "t", "T", "f", "F":
@@ -573,6 +997,15 @@ func cast(s string, r bool, t string) interface{} {
 	return s
 }
 
+// RecastAsJsonNumber causes numeric values to be decoded as json.Number values - the
+// original text - rather than float64, int64 or uint64 values when 'cast' is 'true'
+// in NewMapXml, etc. This retains the exact numeric text - e.g., "1.50" or a large
+// integer - through XML->Map->JSON and XML->Map->XML conversions; the encoders write
+// json.Number values verbatim. Values that are not valid JSON numbers - e.g., "+1" - are
+// handled as if RecastAsJsonNumber were 'false'. See CastValuesToInt and CastValuesToFloat;
+// if both are 'false', numeric values are not recast. (This is the XML analog of JsonUseNumber.)
+var RecastAsJsonNumber bool
+
 // pull request, #59
 var castToFloat = true
 
@@ -600,6 +1033,21 @@ func CastValuesToBool(b ...bool) {
 	}
 }
 
+var castToBoolStrict bool
+
+// CastValuesToBoolStrict restricts casting to bool, when the "cast" argument is 'true' in
+// NewMapXml, etc., to the exact values "true" and "false". By default the values that
+// strconv.ParseBool accepts and that begin with 't' or 'f' - e.g., "t", "F", "TRUE" and
+// "False" - are cast to bool as well. Numeric values, such as "1", are never cast to bool.
+// Default is false. (See CastValuesToBool.)
+func CastValuesToBoolStrict(b ...bool) {
+	if len(b) == 0 {
+		castToBoolStrict = !castToBoolStrict
+	} else if len(b) == 1 {
+		castToBoolStrict = b[0]
+	}
+}
+
 // checkTagToSkip - switch to address Issue #58
 
 var checkTagToSkip func(string) bool
@@ -658,6 +1106,8 @@ func XmlCheckIsValid(b ...bool) {
 //    - The key label "#text" is treated as the value for a simple element with attributes.
 //    - Map keys that begin with a hyphen, '-', are interpreted as attributes.
 //      It is an error if the attribute doesn't have a []byte, string, number, or boolean value.
+//    - The key:value pairs of a "#attr" map[string]interface{} value are also interpreted as
+//      attributes - as decoded if DecodeAttrsAsMap() has been called; both forms can be used.
 //    - Map value type encoding:
 //          > string, bool, float64, int, int32, int64, float32: per "%v" formating
 //          > []bool, []uint8: by casting to string
@@ -670,10 +1120,14 @@ func XmlCheckIsValid(b ...bool) {
 // The attributes tag=value pairs are alphabetized by "tag".  Also, when encoding map[string]interface{} values -
 // complex elements, etc. - the key:value pairs are alphabetized by key so the resulting tags will appear sorted.
 func (mv Map) Xml(rootTag ...string) ([]byte, error) {
+	return mv.xml(new(pretty), rootTag...) // 'pretty' is just a stub
+}
+
+// xml is mv.Xml() with the per-call encoding options in 'p'.
+func (mv Map) xml(p *pretty, rootTag ...string) ([]byte, error) {
 	m := map[string]interface{}(mv)
 	var err error
 	b := new(bytes.Buffer)
-	p := new(pretty) // just a stub
 
 	if len(m) == 1 && len(rootTag) == 0 {
 		for key, value := range m {
@@ -699,7 +1153,7 @@ func (mv Map) Xml(rootTag ...string) ([]byte, error) {
 		err = marshalMapToXmlIndent(false, b, DefaultRootTag, m, p)
 	}
 done:
-	if xmlCheckIsValid {
+	if p.checkIsValid() {
 		d := xml.NewDecoder(bytes.NewReader(b.Bytes()))
 		for {
 			_, err = d.Token()
@@ -711,6 +1165,9 @@ done:
 			}
 		}
 	}
+	if err == nil && p.trailingNewline() {
+		err = b.WriteByte('\n')
+	}
 	return b.Bytes(), err
 }
 
@@ -769,6 +1226,123 @@ func (mv Map) XmlIndentWriterRaw(xmlWriter io.Writer, prefix, indent string, roo
 }
 */
 
+// XmlWithCDATA encodes the Map as XML, as with mv.Xml(), except that the text of the
+// elements at 'paths' is encoded as a CDATA section rather than being escaped - see
+// XMLEscapeChars(). This avoids extensive entity escaping of a large embedded markup
+// value while all other text is encoded normally. The 'paths' are dot-separated element
+// paths of the encoded XML, including the root tag - e.g., "doc.body" - without wildcards
+// or list indexes; all members of a list at a path are encoded as CDATA.
+// If the text contains "]]>", it is split across two CDATA sections.
+func (mv Map) XmlWithCDATA(paths []string, rootTag ...string) ([]byte, error) {
+	p := new(pretty)
+	p.cdata = cdataPaths(paths)
+	return mv.xml(p, rootTag...)
+}
+
+// XmlIndentWithCDATA is mv.XmlIndent() with CDATA text for the elements at 'paths'.
+// See mv.XmlWithCDATA().
+func (mv Map) XmlIndentWithCDATA(paths []string, prefix, indent string, rootTag ...string) ([]byte, error) {
+	b := new(bytes.Buffer)
+	p := new(pretty)
+	p.indent = indent
+	p.padding = prefix
+	p.maxLineWidth = xmlIndentMaxLineWidth
+	p.cdata = cdataPaths(paths)
+
+	if err := marshalMapToXmlIndentRoot(b, map[string]interface{}(mv), p, rootTag...); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func cdataPaths(paths []string) map[string]bool {
+	cdata := make(map[string]bool, len(paths))
+	for _, v := range paths {
+		cdata[v] = true
+	}
+	return cdata
+}
+
+// XmlWrapArrays encodes the Map as XML, as with mv.Xml(), except that the list values
+// for the keys in 'wrap' are encoded as the subelements of a container element with the
+// mapped tag, rather than as unwrapped repeated elements. Thus, with 'wrap' as
+// map["item":"items"], {"doc":{"item":["a","b"]}} is encoded as:
+//	<doc><items><item>a</item><item>b</item></items></doc>
+// An empty list is encoded as an empty container element. Values for the keys that are
+// not lists are not wrapped.
+func (mv Map) XmlWrapArrays(wrap map[string]string, rootTag ...string) ([]byte, error) {
+	p := new(pretty)
+	p.wrap = wrap
+	return mv.xml(p, rootTag...)
+}
+
+// XmlMemberTags encodes the Map as XML, as with mv.Xml(), except that the list values
+// for the keys in 'members' are encoded as the subelements, with the mapped tag, of a
+// single element for the key, rather than as repeated elements for the key. Thus, with
+// 'members' as map["tags":"tag"], {"doc":{"tags":["a","b"]}} is encoded as:
+//	<doc><tags><tag>a</tag><tag>b</tag></tags></doc>
+// An empty list is encoded as an empty element for the key. Values for the keys that are
+// not lists are encoded as with mv.Xml(). See mv.XmlWrapArrays() for naming the container.
+func (mv Map) XmlMemberTags(members map[string]string, rootTag ...string) ([]byte, error) {
+	p := new(pretty)
+	p.members = members
+	return mv.xml(p, rootTag...)
+}
+
+// NilPolicy is the encoding of nil element values for mv.XmlWithNilPolicy().
+type NilPolicy int
+
+const (
+	EmptyNil NilPolicy = iota // an empty element, <key/>; the mv.Xml() encoding
+	OmitNil                   // no element
+	XsiNil                    // an empty element with an xsi:nil="true" attribute, <key xsi:nil="true"/>
+)
+
+// XmlWithNilPolicy encodes the Map as XML, as with mv.Xml(), with nil element values
+// encoded per 'policy'. For XsiNil the "xsi" name space prefix is not declared; add an
+// "xmlns:xsi" attribute, "http://www.w3.org/2001/XMLSchema-instance", to the root element
+// - e.g., the "-xmlns:xsi" key - for the XML to be name space well-formed.
+// See the NilPolicy field of EncodeOptions for indented XML.
+func (mv Map) XmlWithNilPolicy(policy NilPolicy, rootTag ...string) ([]byte, error) {
+	p := new(pretty)
+	p.nilPolicy = policy
+	return mv.xml(p, rootTag...)
+}
+
+// XmlHeaderEncoding is the encoding name used by XmlWithHeader and XmlIndentWithHeader
+// if an 'encoding' argument value is not provided.
+const XmlHeaderEncoding = "UTF-8"
+
+// XmlWithHeader encodes the Map as XML preceded by an XML declaration:
+//	<?xml version="1.0" encoding="<encoding>"?>
+// The 'encoding' value is written verbatim - the XML is not transcoded, it is just
+// labeled. If 'encoding' is "", XmlHeaderEncoding is used.
+// See Xml() for encoding rules.
+func (mv Map) XmlWithHeader(encoding string, rootTag ...string) ([]byte, error) {
+	x, err := mv.Xml(rootTag...)
+	if err != nil {
+		return x, err
+	}
+	return append(xmlHeader(encoding), x...), nil
+}
+
+// XmlIndentWithHeader encodes the Map as pretty XML preceded by an XML declaration.
+// See XmlWithHeader() for handling of 'encoding' and Xml() for encoding rules.
+func (mv Map) XmlIndentWithHeader(encoding, prefix, indent string, rootTag ...string) ([]byte, error) {
+	x, err := mv.XmlIndent(prefix, indent, rootTag...)
+	if err != nil {
+		return x, err
+	}
+	return append(xmlHeader(encoding), x...), nil
+}
+
+func xmlHeader(encoding string) []byte {
+	if encoding == "" {
+		encoding = XmlHeaderEncoding
+	}
+	return []byte(`<?xml version="1.0" encoding="` + encoding + `"?>` + "\n")
+}
+
 // -------------------- END: mv.Xml & mv.XmlWriter -------------------------------
 
 // --------------  Handle XML stream by processing Map value --------------------
@@ -919,21 +1493,100 @@ func (b *byteReader) ReadByte() (byte, error) {
 	return c, err
 }
 
+// ----------------------- limit the size of a XML doc from an io.Reader -----------
+
+// maximum XML doc size for io.Reader decoding - see SetXmlReaderMaxMsgSize.
+var xmlReaderMaxMsgSize int64
+
+// XmlMsgTooLargeError is returned if a XML doc exceeds the SetXmlReaderMaxMsgSize limit.
+var XmlMsgTooLargeError = errors.New("XML message exceeds maximum size")
+
+// SetXmlReaderMaxMsgSize sets the maximum number of bytes that NewMapXmlReader(),
+// NewMapXmlReaderRaw(), NewMapXmlSeqReader(), etc. - and so HandleXmlReader(), etc. -
+// will read for a single XML doc. If the limit is reached before the doc has been decoded,
+// XmlMsgTooLargeError is returned. This protects long-lived stream consumers from an
+// oversized or never-ending message. The default, 0, is no limit.
+//	NOTE: after XmlMsgTooLargeError the io.Reader is positioned within the oversized
+//	      XML doc, so the errHandler for HandleXmlReader(), etc., should normally stop
+//	      processing the stream.
+func SetXmlReaderMaxMsgSize(n int64) {
+	if n < 0 {
+		n = 0
+	}
+	xmlReaderMaxMsgSize = n
+}
+
+// limitXmlReader wraps the io.ByteReader 'r' to enforce xmlReaderMaxMsgSize, if set.
+func limitXmlReader(r io.Reader) io.Reader {
+	if xmlReaderMaxMsgSize == 0 {
+		return r
+	}
+	return &limitByteReader{r: r.(io.ByteReader), max: xmlReaderMaxMsgSize}
+}
+
+type limitByteReader struct {
+	r   io.ByteReader
+	n   int64
+	max int64
+}
+
+// Need for io.Reader interface; xml.Decoder uses ReadByte.
+func (l *limitByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	c, err := l.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	p[0] = c
+	return 1, nil
+}
+
+func (l *limitByteReader) ReadByte() (byte, error) {
+	if l.n >= l.max {
+		return 0, XmlMsgTooLargeError
+	}
+	c, err := l.r.ReadByte()
+	if err == nil {
+		l.n++
+	}
+	return c, err
+}
+
 // ----------------------- END: io.TeeReader hack -----------------------------------
 
 // ---------------------- XmlIndent - from j2x package ----------------------------
 
 // Encode a map[string]interface{} as a pretty XML string.
-// See Xml for encoding rules.
+// See Xml for encoding rules. As with Xml, sibling elements are always encoded sorted
+// by tag and the members of a list value keep their order, so the output is stable.
 func (mv Map) XmlIndent(prefix, indent string, rootTag ...string) ([]byte, error) {
-	m := map[string]interface{}(mv)
-
-	var err error
 	b := new(bytes.Buffer)
 	p := new(pretty)
 	p.indent = indent
 	p.padding = prefix
+	p.maxLineWidth = xmlIndentMaxLineWidth
+
+	err := marshalMapToXmlIndentRoot(b, map[string]interface{}(mv), p, rootTag...)
+	if xmlCheckIsValid {
+		d := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+		for {
+			_, err = d.Token()
+			if err == io.EOF {
+				err = nil
+				break
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.Bytes(), err
+}
 
+// marshalMapToXmlIndentRoot handles the root tag for mv.XmlIndent() and mv.XmlIndentTo().
+func marshalMapToXmlIndentRoot(b *bytes.Buffer, m map[string]interface{}, p *pretty, rootTag ...string) error {
+	var err error
 	if len(m) == 1 && len(rootTag) == 0 {
 		// this can extract the key for the single map element
 		// use it if it isn't a key for a list
@@ -949,27 +1602,204 @@ func (mv Map) XmlIndent(prefix, indent string, rootTag ...string) ([]byte, error
 	} else {
 		err = marshalMapToXmlIndent(true, b, DefaultRootTag, m, p)
 	}
-	if xmlCheckIsValid {
-		d := xml.NewDecoder(bytes.NewReader(b.Bytes()))
-		for {
-			_, err = d.Token()
-			if err == io.EOF {
-				err = nil
-				break
-			} else if err != nil {
-				return nil, err
-			}
-		}
+	if err == nil && p.trailingNewline() {
+		err = b.WriteByte('\n')
+	}
+	return err
+}
+
+// XmlIndentTo encodes the Map as pretty XML, as with mv.XmlIndent(), on the Writer and
+// returns the number of bytes written. The encoded XML is flushed to 'w' as it is encoded,
+// XmlIndentToFlushSize bytes at a time, so the full document is not held in memory.
+//	NOTE: XmlCheckIsValid() does not apply, since the encoded XML is not retained.
+func (mv Map) XmlIndentTo(w io.Writer, prefix, indent string, rootTag ...string) (int64, error) {
+	b := new(bytes.Buffer)
+	p := new(pretty)
+	p.indent = indent
+	p.padding = prefix
+	p.maxLineWidth = xmlIndentMaxLineWidth
+	p.out = &xmlFlusher{w: w}
+
+	if err := marshalMapToXmlIndentRoot(b, map[string]interface{}(mv), p, rootTag...); err != nil {
+		return p.out.n, err
+	}
+	err := p.out.flush(b)
+	return p.out.n, err
+}
+
+// XmlIndentToFlushSize is the encoding buffer size at which mv.XmlIndentTo() writes
+// the encoded XML on the Writer.
+var XmlIndentToFlushSize = 4096
+
+// xmlFlusher writes the encoding buffer on an io.Writer - see mv.XmlIndentTo().
+type xmlFlusher struct {
+	w io.Writer
+	n int64 // bytes written
+}
+
+func (f *xmlFlusher) flush(b *bytes.Buffer) error {
+	n, err := b.WriteTo(f.w)
+	f.n += n
+	return err
+}
+
+// XmlIndent attribute wrapping - see SetXmlIndentMaxLineWidth.
+var xmlIndentMaxLineWidth int
+
+// SetXmlIndentMaxLineWidth sets the maximum width of an element start tag for
+// mv.XmlIndent(), etc. If the start tag - including the prefix and indentation - is
+// longer than 'w', each attribute is written on its own line, indented one level
+// beneath the element:
+//	<elem
+//	  attr1="value 1"
+//	  attr2="value 2">
+// The default, 0, disables wrapping - all attributes are on the start tag line.
+// (Not applicable to MapSeq values.)
+func SetXmlIndentMaxLineWidth(w int) {
+	if w < 0 {
+		w = 0
+	}
+	xmlIndentMaxLineWidth = w
+}
+
+// float attribute value digits - see SetXmlAttrFloatPrecision.
+var xmlAttrFloatPrecision int
+
+// SetXmlAttrFloatPrecision sets the number of digits after the decimal point for
+// float64 and float32 attribute values encoded by mv.Xml(), mv.XmlIndent(), etc.
+// Attribute values are always in plain decimal notation - e.g., "1000000000000000000000"
+// rather than "1e+21". The default, 0, uses the fewest digits that represent the
+// value exactly; with SetXmlAttrFloatPrecision(2) the value 1.5 is encoded as "1.50".
+// (Not applicable to MapSeq values.)
+func SetXmlAttrFloatPrecision(n int) {
+	if n < 0 {
+		n = 0
+	}
+	xmlAttrFloatPrecision = n
+}
+
+// encode empty lists as empty elements - see XmlEmitEmptySlices.
+var xmlEmitEmptySlices = true
+
+// XmlEmitEmptySlices determines how mv.Xml(), mv.XmlIndent(), AnyXml(), etc., encode
+// empty list values, []interface{}{} or []string{}. By default an empty list is encoded
+// as an empty element, "<tag/>", so that the container is present. If set to 'false'
+// the element is omitted entirely. If called with no argument, the encoding is toggled.
+// (Not applicable to MapSeq values.)
+func XmlEmitEmptySlices(b ...bool) {
+	if len(b) == 0 {
+		xmlEmitEmptySlices = !xmlEmitEmptySlices
+	} else if len(b) == 1 {
+		xmlEmitEmptySlices = b[0]
+	}
+}
+
+// terminate the encoded XML with a newline - see XmlTrailingNewline.
+var xmlTrailingNewline bool
+
+// XmlTrailingNewline causes mv.Xml(), mv.XmlIndent(), etc., to append a newline, "\n",
+// to the encoded XML document - as expected by tools that compare or concatenate files.
+// If called with no argument, the setting is toggled. (Not applicable to MapSeq values.)
+func XmlTrailingNewline(b ...bool) {
+	if len(b) == 0 {
+		xmlTrailingNewline = !xmlTrailingNewline
+	} else if len(b) == 1 {
+		xmlTrailingNewline = b[0]
 	}
-	return b.Bytes(), err
 }
 
 type pretty struct {
-	indent   string
-	cnt      int
-	padding  string
-	mapDepth int
-	start    int
+	indent       string
+	cnt          int
+	padding      string
+	mapDepth     int
+	start        int
+	maxLineWidth int               // wrap attributes if the start tag is longer; 0 == no wrapping
+	out          *xmlFlusher       // if not 'nil', flush the encoded XML as elements are closed
+	cdata        map[string]bool   // element paths with CDATA text - see XmlWithCDATA
+	path         string            // path of the element, if cdata != nil
+	opts         *EncodeOptions    // per-call settings - see mv.Marshal(); if 'nil' the package settings apply
+	wrap         map[string]string // container tags for list keys - see XmlWrapArrays
+	members      map[string]string // member tags for list keys - see XmlMemberTags
+	wrapped      string            // the list key that is the value of a container element
+	nilPolicy    NilPolicy         // encoding of nil values - see XmlWithNilPolicy
+	canonical    bool              // c14n attribute order, no comments - see XmlCanonical
+}
+
+// escapeChars, goEmptyElemSyntax, emitEmptySlices, trailingNewline and checkIsValid
+// report the encoding settings for the call.
+func (p *pretty) escapeChars() bool {
+	if p.opts != nil {
+		return p.opts.EscapeChars
+	}
+	return xmlEscapeChars
+}
+
+// attrFloatPrecision is the strconv.FormatFloat precision for float attribute values.
+func (p *pretty) attrFloatPrecision() int {
+	n := xmlAttrFloatPrecision
+	if p.opts != nil {
+		n = p.opts.AttrPrecision
+	}
+	if n <= 0 {
+		return -1
+	}
+	return n
+}
+
+// escapeMode is the set of characters escaped if escapeChars() is 'true'.
+func (p *pretty) escapeMode() XmlEscapeMode {
+	if p.opts != nil {
+		return p.opts.EscapeMode
+	}
+	return xmlEscapeMode
+}
+
+func (p *pretty) goEmptyElemSyntax() bool {
+	if p.opts != nil {
+		return p.opts.GoEmptyElemSyntax
+	}
+	return useGoXmlEmptyElemSyntax
+}
+
+func (p *pretty) emitEmptySlices() bool {
+	if p.opts != nil {
+		return !p.opts.OmitEmptySlices
+	}
+	return xmlEmitEmptySlices
+}
+
+func (p *pretty) trailingNewline() bool {
+	if p.opts != nil {
+		return p.opts.TrailingNewline
+	}
+	return xmlTrailingNewline
+}
+
+func (p *pretty) checkIsValid() bool {
+	if p.opts != nil {
+		return p.opts.CheckIsValid
+	}
+	return xmlCheckIsValid
+}
+
+// useCDATA reports whether the element text is to be encoded as CDATA.
+func (p *pretty) useCDATA() bool {
+	return p.cdata != nil && p.cdata[p.path]
+}
+
+// wraps reports whether a list value for 'key' is encoded in a container element.
+func (p *pretty) wraps(key string) bool {
+	_, ok := p.wrap[key]
+	if !ok {
+		_, ok = p.members[key]
+	}
+	return ok && p.wrapped != key
+}
+
+// cdataText returns 's' as a CDATA section; "]]>" is split across two sections.
+func cdataText(s string) string {
+	return "<![CDATA[" + strings.Replace(s, "]]>", "]]]]><![CDATA[>", -1) + "]]>"
 }
 
 func (p *pretty) Indent() {
@@ -984,15 +1814,167 @@ func (p *pretty) Outdent() {
 	}
 }
 
+// XmlIterativeMarshal causes mv.Xml(), mv.XmlIndent(), AnyXml(), etc., to encode
+// the Map using an explicit stack rather than recursion. The encoded XML is the same;
+// but the depth of the Map value is not limited by the goroutine stack size - which
+// may be of concern for deeply nested Map values from untrusted sources.
+// (Not applicable to MapSeq values.)
+var XmlIterativeMarshal bool
+
 // where the work actually happens
 // returns an error if an attribute is not atomic
 // NOTE: 01may20 - replaces mapToXmlIndent(); uses bytes.Buffer instead for string appends.
 func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value interface{}, pp *pretty) error {
+	if XmlIterativeMarshal {
+		return marshalMapToXmlIterative(doIndent, b, key, value, pp)
+	}
+	return marshalMapToXmlRecursive(doIndent, b, key, value, pp)
+}
+
+// xmlElem is the encoding state of an element between openXmlElem and closeXmlElem.
+type xmlElem struct {
+	key      string
+	value    interface{}
+	p        *pretty
+	endTag   bool
+	isSimple bool
+	elen     int
+	isMap    bool             // 'children' are subelements
+	isList   bool             // 'children' are list members; there is no end tag
+	children [][2]interface{} // key:value pairs to encode between the start and end tags
+	next     int              // index of the next member of 'children' to encode
+}
+
+// beforeChild and afterChild handle indentation of subelements and list members.
+func (e *xmlElem) beforeChild(doIndent bool, k string, v interface{}) {
+	if !doIndent {
+		return
+	}
+	if _, ok := v.([]interface{}); ok && !e.isList && !e.p.wraps(k) {
+		return // handled in []interface{} case
+	}
+	e.p.Indent()
+}
+
+func (e *xmlElem) afterChild(doIndent bool, k string, v interface{}) {
+	if !doIndent {
+		return
+	}
+	if _, ok := v.([]interface{}); ok && !e.isList && !e.p.wraps(k) {
+		return // handled in []interface{} case
+	}
+	e.p.Outdent()
+}
+
+func marshalMapToXmlRecursive(doIndent bool, b *bytes.Buffer, key string, value interface{}, pp *pretty) error {
+	e, err := openXmlElem(doIndent, b, key, value, pp)
+	if err != nil {
+		return err
+	}
+	for _, v := range e.children {
+		e.beforeChild(doIndent, v[0].(string), v[1])
+		if err := marshalMapToXmlRecursive(doIndent, b, v[0].(string), v[1], e.p); err != nil {
+			return err
+		}
+		e.afterChild(doIndent, v[0].(string), v[1])
+	}
+	return closeXmlElem(doIndent, b, e)
+}
+
+// marshalMapToXmlIterative is marshalMapToXmlRecursive using an explicit stack.
+func marshalMapToXmlIterative(doIndent bool, b *bytes.Buffer, key string, value interface{}, pp *pretty) error {
+	e, err := openXmlElem(doIndent, b, key, value, pp)
+	if err != nil {
+		return err
+	}
+	stack := []*xmlElem{e}
+	for len(stack) > 0 {
+		e = stack[len(stack)-1]
+		if e.next > 0 {
+			e.afterChild(doIndent, e.children[e.next-1][0].(string), e.children[e.next-1][1])
+		}
+		if e.next == len(e.children) {
+			if err := closeXmlElem(doIndent, b, e); err != nil {
+				return err
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		v := e.children[e.next]
+		e.next++
+		e.beforeChild(doIndent, v[0].(string), v[1])
+		ce, err := openXmlElem(doIndent, b, v[0].(string), v[1], e.p)
+		if err != nil {
+			return err
+		}
+		stack = append(stack, ce)
+	}
+	return nil
+}
+
+// openXmlElem encodes the start tag, attributes and simple value, if any, of an element.
+// Subelements or list members are returned in xmlElem.children for encoding before
+// calling closeXmlElem.
+func openXmlElem(doIndent bool, b *bytes.Buffer, key string, value interface{}, pp *pretty) (*xmlElem, error) {
 	var err error
 	var endTag bool
 	var isSimple bool
 	var elen int
-	p := &pretty{pp.indent, pp.cnt, pp.padding, pp.mapDepth, pp.start}
+	var isMap bool
+	var children [][2]interface{}
+	pc := *pp
+	p := &pc
+	// per XmlWrapArrays, a list value is encoded as the value of a container element
+	// per XmlMemberTags, a list value is encoded as the members of a single element
+	if p.wrap != nil || p.members != nil {
+		wrapped := p.wrapped == key
+		p.wrapped = ""
+		if tag, ok := p.wrap[key]; ok && !wrapped {
+			switch value.(type) {
+			case []interface{}, []string:
+				if reflect.ValueOf(value).Len() == 0 {
+					key, value = tag, ""
+				} else {
+					key, value, p.wrapped = tag, map[string]interface{}{key: value}, key
+				}
+			}
+		} else if tag, ok := p.members[key]; ok && !wrapped {
+			switch value.(type) {
+			case []interface{}, []string:
+				if reflect.ValueOf(value).Len() == 0 {
+					value = ""
+				} else {
+					value, p.wrapped = map[string]interface{}{tag: value}, tag
+				}
+			}
+		}
+	}
+	// per XmlEmitSourceComments, the source line is encoded as a comment before the element
+	if XmlEmitSourceComments && !p.canonical {
+		if m, ok := value.(map[string]interface{}); ok {
+			if line, ok := m[SourceLineKey]; ok {
+				c := "<!-- line " + fmt.Sprint(line) + " -->"
+				if doIndent {
+					c = p.padding + c + "\n"
+				}
+				if _, err = b.WriteString(c); err != nil {
+					return nil, err
+				}
+				value = withoutSourcePositions(m)
+			}
+		}
+	}
+	if p.cdata != nil {
+		switch value.(type) {
+		case []interface{}, []string:
+			// list members have the list's path
+		default:
+			if p.path != "" {
+				p.path += "."
+			}
+			p.path += key
+		}
+	}
 
 	// per issue #48, 18apr18 - try and coerce maps to map[string]interface{}
 	// Don't need for mapToXmlSeqIndent, since maps there are decoded by NewMapXmlSeq().
@@ -1019,12 +2001,20 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 	case []map[string]interface{}, []string, []float64, []bool, []int, []int32, []int64, []float32, []json.Number:
 	case []interface{}:
 	case nil:
-		value = ""
+		switch p.nilPolicy {
+		case OmitNil:
+			// nothing to encode
+			return &xmlElem{key: key, value: value, p: p, isList: true}, nil
+		case XsiNil:
+			// encoded as nil
+		default:
+			value = ""
+		}
 	default:
 		// see if value is a struct, if so marshal using encoding/xml package
 		if reflect.ValueOf(value).Kind() == reflect.Struct {
 			if v, err := xml.Marshal(value); err != nil {
-				return err
+				return nil, err
 			} else {
 				value = string(v)
 			}
@@ -1041,15 +2031,15 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 			// list processing handles indentation for all elements
 		default:
 			if _, err = b.WriteString(p.padding); err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 	switch value.(type) {
-	case []interface{}:
+	case []interface{}, []string:
 	default:
 		if _, err = b.WriteString(`<` + key); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -1057,56 +2047,65 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 	case map[string]interface{}:
 		vv := value.(map[string]interface{})
 		lenvv := len(vv)
-		// scan out attributes - attribute keys have prepended attrPrefix
-		attrlist := make([][2]string, len(vv))
-		var n int
-		var ss string
+		// scan out attributes - attribute keys have prepended attrPrefix,
+		// or are the key:value pairs of a "#attr" map value - see DecodeAttrsAsMap
+		attrlist := make([][2]string, 0, len(vv))
+		var n int // number of keys that are attributes
 		for k, v := range vv {
 			if lenAttrPrefix > 0 && lenAttrPrefix < len(k) && k[:lenAttrPrefix] == attrPrefix {
-				switch v.(type) {
-				case string:
-					if xmlEscapeChars {
-						ss = escapeChars(v.(string))
-					} else {
-						ss = v.(string)
-					}
-					attrlist[n][0] = k[lenAttrPrefix:]
-					attrlist[n][1] = ss
-				case float64, bool, int, int32, int64, float32, json.Number:
-					attrlist[n][0] = k[lenAttrPrefix:]
-					attrlist[n][1] = fmt.Sprintf("%v", v)
-				case []byte:
-					if xmlEscapeChars {
-						ss = escapeChars(string(v.([]byte)))
-					} else {
-						ss = string(v.([]byte))
+				a, err := attrValue(k, v, p)
+				if err != nil {
+					return nil, err
+				}
+				attrlist = append(attrlist, [2]string{k[lenAttrPrefix:], a})
+				n++
+			} else if am, ok := v.(map[string]interface{}); ok && k == "#attr" {
+				for kk, vv := range am {
+					a, err := attrValue(kk, vv, p)
+					if err != nil {
+						return nil, err
 					}
-					attrlist[n][0] = k[lenAttrPrefix:]
-					attrlist[n][1] = ss
-				default:
-					return fmt.Errorf("invalid attribute value for: %s:<%T>", k, v)
+					attrlist = append(attrlist, [2]string{kk, a})
 				}
 				n++
 			}
 		}
-		if n > 0 {
-			attrlist = attrlist[:n]
-			sort.Sort(attrList(attrlist))
+		if len(attrlist) > 0 {
+			if p.canonical {
+				sort.Sort(canonicalAttrList(attrlist))
+			} else {
+				sort.Sort(attrList(attrlist))
+			}
+			// if the start tag is too long, put each attribute on its own line
+			var wrap bool
+			if doIndent && p.maxLineWidth > 0 {
+				w := len(p.padding) + len(key) + 2 // '<' + key + '>'
+				for _, v := range attrlist {
+					w += len(v[0]) + len(v[1]) + 4 // ' ' + k + '="' + v + '"'
+				}
+				wrap = w > p.maxLineWidth
+			}
 			for _, v := range attrlist {
+				if wrap {
+					if _, err = b.WriteString("\n" + p.padding + p.indent + v[0] + `="` + v[1] + `"`); err != nil {
+						return nil, err
+					}
+					continue
+				}
 				if _, err = b.WriteString(` ` + v[0] + `="` + v[1] + `"`); err != nil {
-					return err
+					return nil, err
 				}
 			}
 		}
 		// only attributes?
 		if n == lenvv {
-			if useGoXmlEmptyElemSyntax {
-				if _, err = b.WriteString(`</` + key + ">"); err != nil {
-					return err
+			if p.goEmptyElemSyntax() {
+				if _, err = b.WriteString(`></` + key + ">"); err != nil {
+					return nil, err
 				}
 			} else {
 				if _, err = b.WriteString(`/>`); err != nil {
-					return err
+					return nil, err
 				}
 			}
 			break
@@ -1118,20 +2117,24 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 			// just the value and attributes
 			switch v.(type) {
 			case string:
-				if xmlEscapeChars {
-					v = escapeChars(v.(string))
+				if p.useCDATA() {
+					v = cdataText(v.(string))
+				} else if p.escapeChars() {
+					v = escapeText(v.(string), p.escapeMode())
 				} else {
 					v = v.(string)
 				}
 			case []byte:
-				if xmlEscapeChars {
-					v = escapeChars(string(v.([]byte)))
+				if p.useCDATA() {
+					v = cdataText(string(v.([]byte)))
+				} else if p.escapeChars() {
+					v = escapeText(string(v.([]byte)), p.escapeMode())
 				} else {
 					v = string(v.([]byte))
 				}
 			}
 			if _, err = b.WriteString(">" + fmt.Sprintf("%v", v)); err != nil {
-				return err
+				return nil, err
 			}
 			endTag = true
 			elen = 1
@@ -1142,20 +2145,20 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 			// issue #90
 			switch v.(type) {
 			case string:
-				if xmlEscapeChars {
-					v = escapeChars(v.(string))
+				if p.escapeChars() {
+					v = escapeText(v.(string), p.escapeMode())
 				} else {
 					v = v.(string)
 				}
 			case []byte:
-				if xmlEscapeChars {
-					v = escapeChars(string(v.([]byte)))
+				if p.escapeChars() {
+					v = escapeText(string(v.([]byte)), p.escapeMode())
 				} else {
 					v = string(v.([]byte))
 				}
 			}
 			if _, err = b.WriteString(">" + fmt.Sprintf("%v", v)); err != nil {
-				return err
+				return nil, err
 			}
 			isComplex = true
 		}
@@ -1163,13 +2166,13 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 		// close tag with possible attributes
 		if !isComplex {
 			if _, err = b.WriteString(">"); err != nil {
-				return err
+				return nil, err
 			}
 		}
 		if doIndent {
 			// *s += "\n"
 			if _, err = b.WriteString("\n"); err != nil {
-				return err
+				return nil, err
 			}
 		}
 		// something more complex
@@ -1185,64 +2188,43 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 			if lenAttrPrefix > 0 && lenAttrPrefix < len(k) && k[:lenAttrPrefix] == attrPrefix {
 				continue
 			}
+			if _, ok := v.(map[string]interface{}); ok && k == "#attr" {
+				continue
+			}
 			elemlist[n][0] = k
 			elemlist[n][1] = v
 			n++
 		}
 		elemlist = elemlist[:n]
 		sort.Sort(elemList(elemlist))
-		var i int
-		for _, v := range elemlist {
-			switch v[1].(type) {
-			case []interface{}:
-			default:
-				if i == 0 && doIndent {
-					p.Indent()
-				}
-			}
-			i++
-			if err := marshalMapToXmlIndent(doIndent, b, v[0].(string), v[1], p); err != nil {
-				return err
-			}
-			switch v[1].(type) {
-			case []interface{}: // handled in []interface{} case
-			default:
-				if doIndent {
-					p.Outdent()
-				}
-			}
-			i--
-		}
-		p.mapDepth--
+		children = elemlist
+		isMap = true
 		endTag = true
 		elen = 1 // we do have some content ...
 	case []interface{}:
 		// special case - found during implementing Issue #23
 		if len(value.([]interface{})) == 0 {
+			if !p.emitEmptySlices() {
+				// nothing to encode
+				return &xmlElem{key: key, value: value, p: p, isList: true}, nil
+			}
 			if doIndent {
 				if _, err = b.WriteString(p.padding + p.indent); err != nil {
-					return err
+					return nil, err
 				}
 			}
 			if _, err = b.WriteString("<" + key); err != nil {
-				return err
+				return nil, err
 			}
 			elen = 0
 			endTag = true
 			break
 		}
-		for _, v := range value.([]interface{}) {
-			if doIndent {
-				p.Indent()
-			}
-			if err := marshalMapToXmlIndent(doIndent, b, key, v, p); err != nil {
-				return err
-			}
-			if doIndent {
-				p.Outdent()
-			}
+		children = make([][2]interface{}, len(value.([]interface{})))
+		for i, v := range value.([]interface{}) {
+			children[i] = [2]interface{}{key, v}
 		}
-		return nil
+		return &xmlElem{key: key, value: value, p: p, isList: true, children: children}, nil
 	case []string:
 		// This was added by https://github.com/slotix ... not a type that
 		// would be encountered if mv generated from NewMapXml, NewMapJson.
@@ -1251,40 +2233,31 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 		//quick fix for []string type
 		//[]string should be treated exaclty as []interface{}
 		if len(value.([]string)) == 0 {
+			if !p.emitEmptySlices() {
+				// nothing to encode
+				return &xmlElem{key: key, value: value, p: p, isList: true}, nil
+			}
 			if doIndent {
 				if _, err = b.WriteString(p.padding + p.indent); err != nil {
-					return err
+					return nil, err
 				}
 			}
 			if _, err = b.WriteString("<" + key); err != nil {
-				return err
+				return nil, err
 			}
 			elen = 0
 			endTag = true
 			break
 		}
-		for _, v := range value.([]string) {
-			if doIndent {
-				p.Indent()
-			}
-			if err := marshalMapToXmlIndent(doIndent, b, key, v, p); err != nil {
-				return err
-			}
-			if doIndent {
-				p.Outdent()
-			}
+		children = make([][2]interface{}, len(value.([]string)))
+		for i, v := range value.([]string) {
+			children[i] = [2]interface{}{key, v}
 		}
-		return nil
+		return &xmlElem{key: key, value: value, p: p, isList: true, children: children}, nil
 	case nil:
-		// terminate the tag
-		if doIndent {
-			// *s += p.padding
-			if _, err = b.WriteString(p.padding); err != nil {
-				return err
-			}
-		}
-		if _, err = b.WriteString("<" + key); err != nil {
-			return err
+		// per XsiNil; the start tag has been opened
+		if _, err = b.WriteString(` xsi:nil="true"`); err != nil {
+			return nil, err
 		}
 		endTag, isSimple = true, true
 		break
@@ -1293,8 +2266,16 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 		switch value.(type) {
 		case string:
 			v := value.(string)
-			if xmlEscapeChars {
-				v = escapeChars(v)
+			if p.useCDATA() {
+				if elen = len(v); elen > 0 {
+					if _, err = b.WriteString(">" + cdataText(v)); err != nil {
+						return nil, err
+					}
+				}
+				break
+			}
+			if p.escapeChars() {
+				v = escapeText(v, p.escapeMode())
 			}
 			elen = len(v)
 			if elen > 0 {
@@ -1307,38 +2288,40 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 						if len(nsAttrs) == 2 {
 							v = strings.Join(parts[1:], " ")
 							if _, err = b.WriteString(` ` + nsAttrs[0] + `="` + nsAttrs[1] + `"`); err != nil {
-								return err
+								return nil, err
 							}
 						}
 					}
 				}
 
 				if _, err = b.WriteString(">" + v); err != nil {
-					return err
+					return nil, err
 				}
 			}
 		case float64, bool, int, int32, int64, float32, json.Number:
 			v := fmt.Sprintf("%v", value)
 			elen = len(v) // always > 0
 			if _, err = b.WriteString(">" + v); err != nil {
-				return err
+				return nil, err
 			}
 		case []byte: // NOTE: byte is just an alias for uint8
 			// similar to how xml.Marshal handles []byte structure members
 			v := string(value.([]byte))
-			if xmlEscapeChars {
-				v = escapeChars(v)
+			if p.useCDATA() {
+				v = cdataText(v)
+			} else if p.escapeChars() {
+				v = escapeText(v, p.escapeMode())
 			}
 			elen = len(v)
 			if elen > 0 {
 				// *s += ">" + v
 				if _, err = b.WriteString(">" + v); err != nil {
-					return err
+					return nil, err
 				}
 			}
 		default:
 			if _, err = b.WriteString(">"); err != nil {
-				return err
+				return nil, err
 			}
 			var v []byte
 			var err error
@@ -1349,13 +2332,13 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 			}
 			if err != nil {
 				if _, err = b.WriteString(">UNKNOWN"); err != nil {
-					return err
+					return nil, err
 				}
 			} else {
 				elen = len(v)
 				if elen > 0 {
 					if _, err = b.Write(v); err != nil {
-						return err
+						return nil, err
 					}
 				}
 			}
@@ -1363,21 +2346,57 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 		isSimple = true
 		endTag = true
 	}
-	if endTag {
+	return &xmlElem{key: key, value: value, p: p, endTag: endTag, isSimple: isSimple, elen: elen,
+		isMap: isMap, children: children}, nil
+}
+
+// attrValue returns the encoded value of the attribute 'k'.
+func attrValue(k string, v interface{}, p *pretty) (string, error) {
+	switch v.(type) {
+	case string:
+		if p.escapeChars() {
+			return escapeAttr(v.(string), p.escapeMode()), nil
+		}
+		return v.(string), nil
+	case float64:
+		return strconv.FormatFloat(v.(float64), 'f', p.attrFloatPrecision(), 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v.(float32)), 'f', p.attrFloatPrecision(), 32), nil
+	case bool, int, int32, int64, json.Number:
+		return fmt.Sprintf("%v", v), nil
+	case []byte:
+		if p.escapeChars() {
+			return escapeAttr(string(v.([]byte)), p.escapeMode()), nil
+		}
+		return string(v.([]byte)), nil
+	}
+	return "", fmt.Errorf("invalid attribute value for: %s:<%T>", k, v)
+}
+
+// closeXmlElem encodes the end tag of an element, if required, after any subelements.
+func closeXmlElem(doIndent bool, b *bytes.Buffer, e *xmlElem) error {
+	var err error
+	if e.isList {
+		return nil
+	}
+	if e.isMap {
+		e.p.mapDepth--
+	}
+	if e.endTag {
 		if doIndent {
-			if !isSimple {
-				if _, err = b.WriteString(p.padding); err != nil {
+			if !e.isSimple {
+				if _, err = b.WriteString(e.p.padding); err != nil {
 					return err
 				}
 			}
 		}
-		if elen > 0 || useGoXmlEmptyElemSyntax {
-			if elen == 0 {
+		if e.elen > 0 || e.p.goEmptyElemSyntax() {
+			if e.elen == 0 {
 				if _, err = b.WriteString(">"); err != nil {
 					return err
 				}
 			}
-			if _, err = b.WriteString(`</` + key + ">"); err != nil {
+			if _, err = b.WriteString(`</` + e.key + ">"); err != nil {
 				return err
 			}
 		} else {
@@ -1387,12 +2406,15 @@ func marshalMapToXmlIndent(doIndent bool, b *bytes.Buffer, key string, value int
 		}
 	}
 	if doIndent {
-		if p.cnt > p.start {
+		if e.p.cnt > e.p.start {
 			if _, err = b.WriteString("\n"); err != nil {
 				return err
 			}
 		}
-		p.Outdent()
+		e.p.Outdent()
+	}
+	if e.p.out != nil && b.Len() >= XmlIndentToFlushSize {
+		return e.p.out.flush(b)
 	}
 
 	return nil
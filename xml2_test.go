@@ -1,10 +1,12 @@
 package mxj
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"testing"
 )
@@ -320,3 +322,59 @@ func BenchmarkNewStructJsonBooks(b *testing.B) {
 	}
 	// fmt.Println("s jsondoc2:", *s)
 }
+
+// bigMap returns a Map with 'n' list members for the XmlIndentTo tests.
+func bigMap(n int) Map {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{"-seq": fmt.Sprint(i), "name": "item name", "value": float64(i)}
+	}
+	return Map{"doc": map[string]interface{}{"item": items}}
+}
+
+func TestXmlIndentTo(t *testing.T) {
+	for _, n := range []int{1, 1000} {
+		m := bigMap(n)
+		want, err := m.XmlIndent("", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := new(bytes.Buffer)
+		c, err := m.XmlIndentTo(buf, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != string(want) {
+			t.Fatal("got:", buf.String(), "want:", string(want))
+		}
+		if c != int64(len(want)) {
+			t.Fatal("count:", c, "want:", len(want))
+		}
+	}
+}
+
+func BenchmarkXmlIndent(b *testing.B) {
+	m := bigMap(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x, err := m.XmlIndent("", "  ")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = ioutil.Discard.Write(x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkXmlIndentTo(b *testing.B) {
+	m := bigMap(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := m.XmlIndentTo(ioutil.Discard, "", "  "); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -238,3 +239,526 @@ func xehandler(err error, raw []byte) bool {
 	return true
 }
 */
+
+func TestXmlWithHeader(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"elem": "value"}}
+	x, err := m.XmlWithHeader("ISO-8859-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="ISO-8859-1"?>` + "\n" + `<doc><elem>value</elem></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	x, err = m.XmlIndentWithHeader("", "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + "<doc>\n  <elem>value</elem>\n</doc>"
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
+
+func TestXmlEmitEmptySlices(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"a": []interface{}{}, "b": "x", "c": []string{}}}
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><a/><b>x</b><c/></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	x, err = Map{"doc": map[string]interface{}{"a": []string{"x", "y"}}}.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><a>x</a><a>y</a></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	XmlEmitEmptySlices(false)
+	defer XmlEmitEmptySlices(true)
+	x, err = m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><b>x</b></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	x, err = m.XmlIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<doc>\n  <b>x</b>\n</doc>"; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
+
+func TestXmlPathValue(t *testing.T) {
+	doc := []byte(`<doc><book seq="1"><title>A</title></book><book seq="2"><title>B</title></book><count>2</count></doc>`)
+
+	v, err := XmlPathValue(doc, "doc.count", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(float64) != 2 {
+		t.Fatal("doc.count:", v)
+	}
+	v, err = XmlPathValue(doc, "*.book[1].title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.(string) != "B" {
+		t.Fatal("*.book[1].title:", v)
+	}
+	if _, err = XmlPathValue(doc, "doc.*.title"); err != PathNotUniqueError {
+		t.Fatal("doc.*.title, err:", err)
+	}
+	if _, err = XmlPathValue(doc, "doc.author"); err != PathNotExistError {
+		t.Fatal("doc.author, err:", err)
+	}
+	if _, err = XmlPathValue([]byte(`<doc>`), "doc"); err == nil {
+		t.Fatal("no error for bad doc")
+	}
+}
+
+func TestSetMixedTextKey(t *testing.T) {
+	SetMixedTextKey("text")
+	defer SetMixedTextKey("")
+
+	data := []byte(`<doc>before<sub>value</sub><simple id="1">attr text</simple><more>x<text>y</text></more><after><sub/>after</after></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		"doc.text":         "before",
+		"doc.simple.#text": "attr text",
+		"doc.more.#text":   "x",
+		"doc.more.text":    "y",
+		"doc.after.text":   "after",
+	} {
+		v, err := m.ValueForPath(path)
+		if err != nil {
+			t.Fatal(path, err)
+		}
+		if v.(string) != want {
+			t.Fatal(path, "got:", v, "want:", want)
+		}
+	}
+	if _, err = m.ValueForPath("doc.#text"); err == nil {
+		t.Fatal("found doc.#text")
+	}
+}
+
+func TestNewMapXmlReaderProgress(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("<doc>")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&buf, "<item>%d</item>", i)
+	}
+	buf.WriteString("</doc>")
+	size := int64(buf.Len())
+
+	var calls, last int
+	var lastBytes int64
+	m, err := NewMapXmlReaderProgress(&buf, 4, func(bytesRead int64, elements int) {
+		calls++
+		if bytesRead < lastBytes || elements <= last {
+			t.Fatal("progress not increasing:", bytesRead, elements)
+		}
+		lastBytes, last = bytesRead, elements
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 11 elements - called at 4, 8 and at the end
+	if calls != 3 || last != 11 || lastBytes != size {
+		t.Fatal("calls:", calls, "elements:", last, "bytes:", lastBytes, "size:", size)
+	}
+	if v, _ := m.ValuesForPath("doc.item"); len(v) != 10 {
+		t.Fatal("doc.item:", v)
+	}
+}
+
+func TestNewMapXmlMaxArray(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("<doc>")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&buf, "<item><n>%d</n><sub>a</sub><sub>b</sub><sub>c</sub></item>", i)
+	}
+	buf.WriteString("<single>x</single></doc>")
+
+	m, err := NewMapXmlMaxArray(buf.Bytes(), 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValuesForPath("doc.item"); len(v) != 2 {
+		t.Fatal("doc.item:", v)
+	}
+	if v, _ := m.ValuesForPath("doc.item[1].n"); len(v) != 1 || v[0] != float64(1) {
+		t.Fatal("doc.item[1].n:", v)
+	}
+	if v, _ := m.ValuesForPath("doc.item[0].sub"); len(v) != 2 || v[1] != "b" {
+		t.Fatal("doc.item[0].sub:", v)
+	}
+	if v, _ := m.ValueForPath("doc.single"); v != "x" {
+		t.Fatal("doc.single:", v)
+	}
+
+	// a singleton per key
+	if m, err = NewMapXmlMaxArray(buf.Bytes(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.item.sub"); v != "a" {
+		t.Fatal("doc.item.sub:", v)
+	}
+
+	// no limit
+	if m, err = NewMapXmlMaxArray(buf.Bytes(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValuesForPath("doc.item"); len(v) != 10 {
+		t.Fatal("doc.item:", len(v))
+	}
+
+	if _, err = NewMapXmlMaxArray([]byte("<doc><a/><a/><a><b></a></doc>"), 2); err == nil {
+		t.Fatal("no error for malformed discarded element")
+	}
+}
+
+func TestXmlSpacePreserve(t *testing.T) {
+	data := []byte(`<doc>
+	<trim>  text  </trim>
+	<pre xml:space="preserve">  text  </pre>
+	<code xml:space="preserve">
+		<line>  a = 1  </line>
+		<blank>   </blank>
+		<reset xml:space="default">  text  </reset>
+	</code>
+</doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		"doc.trim":             "text",
+		"doc.pre.#text":        "  text  ",
+		"doc.code.line":        "  a = 1  ",
+		"doc.code.blank":       "   ",
+		"doc.code.reset.#text": "text",
+	} {
+		v, err := m.ValueForPath(path)
+		if err != nil {
+			t.Fatal(path, err)
+		}
+		if v.(string) != want {
+			t.Fatalf("%s got: %q want: %q", path, v, want)
+		}
+	}
+	if _, err = m.ValueForPath("doc.code.#text"); err == nil {
+		t.Fatal("white space between subelements decoded as text")
+	}
+}
+
+func TestXmlWithCDATA(t *testing.T) {
+	XMLEscapeChars(true)
+	defer XMLEscapeChars(false)
+	m := Map{"doc": map[string]interface{}{
+		"body":  "<p>a & b</p>",
+		"title": "<title>",
+		"note":  map[string]interface{}{"-type": "x", "#text": "end ]]> here"},
+		"items": map[string]interface{}{"item": []interface{}{"<1>", "<2>"}},
+	}}
+	paths := []string{"doc.body", "doc.note", "doc.items.item"}
+	x, err := m.XmlWithCDATA(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc><body><![CDATA[<p>a & b</p>]]></body><items><item><![CDATA[<1>]]></item><item><![CDATA[<2>]]></item></items>` +
+		`<note type="x"><![CDATA[end ]]]]><![CDATA[> here]]></note><title>&lt;title&gt;</title></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "\nwant:", want)
+	}
+
+	// round trip
+	mm, err := NewMapXml(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := mm.ValueForPath("doc.body"); v.(string) != "<p>a & b</p>" {
+		t.Fatal("doc.body:", v)
+	}
+
+	x, err = m.XmlIndentWithCDATA(paths, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(x, []byte("\n  <body><![CDATA[<p>a & b</p>]]></body>\n")) {
+		t.Fatal("XmlIndentWithCDATA:", string(x))
+	}
+}
+
+func TestSetXmlReaderMaxMsgSize(t *testing.T) {
+	SetXmlReaderMaxMsgSize(32)
+	defer SetXmlReaderMaxMsgSize(0)
+
+	data := `<doc><a>1</a></doc><doc><a>` + strings.Repeat("x", 64) + `</a></doc>`
+	rdr := strings.NewReader(data)
+	if _, err := NewMapXmlReader(rdr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewMapXmlReader(rdr); err != XmlMsgTooLargeError {
+		t.Fatal("err:", err)
+	}
+
+	rdr = strings.NewReader(data)
+	if _, _, err := NewMapXmlReaderRaw(rdr); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewMapXmlReaderRaw(rdr); err != XmlMsgTooLargeError {
+		t.Fatal("raw err:", err)
+	}
+
+	var n int
+	err := HandleXmlReader(strings.NewReader(data),
+		func(m Map) bool { n++; return true },
+		func(err error) bool { return false })
+	if err == nil || n != 1 {
+		t.Fatal("HandleXmlReader:", n, err)
+	}
+}
+
+func TestNewMapXmlUnwrapRoot(t *testing.T) {
+	PrependAttrWithHyphen(true)
+
+	m, root, err := NewMapXmlUnwrapRoot([]byte(`<msg id="1"><body>text</body></msg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != "msg" || m["-id"] != "1" || m["body"] != "text" {
+		t.Fatal("root:", root, "m:", m)
+	}
+
+	m, root, err = NewMapXmlUnwrapRoot([]byte(`<count>2</count>`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != "count" || m["#text"] != float64(2) {
+		t.Fatal("root:", root, "m:", m)
+	}
+
+	m, root, err = NewMapXmlUnwrapRoot([]byte(`<empty/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != "empty" || len(m) != 0 {
+		t.Fatal("root:", root, "m:", m)
+	}
+
+	if _, _, err = NewMapXmlUnwrapRoot([]byte(`<doc>`)); err == nil {
+		t.Fatal("no error for bad XML")
+	}
+}
+
+func TestXmlTrailingNewline(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"a": "1"}}
+	XmlTrailingNewline(true)
+	x, _ := m.Xml()
+	xi, _ := m.XmlIndent("", "  ")
+	xs, _ := MapsToXml([]Map{m, m}, "docs", "")
+	XmlTrailingNewline(false)
+	if want := "<doc><a>1</a></doc>\n"; string(x) != want {
+		t.Fatalf("got: %q want: %q", x, want)
+	}
+	if want := "<doc>\n  <a>1</a>\n</doc>\n"; string(xi) != want {
+		t.Fatalf("got: %q want: %q", xi, want)
+	}
+	if want := "<docs><doc><a>1</a></doc><doc><a>1</a></doc></docs>\n"; string(xs) != want {
+		t.Fatalf("got: %q want: %q", xs, want)
+	}
+
+	if x, _ = m.Xml(); x[len(x)-1] == '\n' {
+		t.Fatalf("trailing newline: %q", x)
+	}
+	if x, _ = m.Marshal(EncodeOptions{TrailingNewline: true}); string(x) != "<doc><a>1</a></doc>\n" {
+		t.Fatalf("Marshal: %q", x)
+	}
+}
+
+func TestSplitCharData(t *testing.T) {
+	PrependAttrWithHyphen(true)
+
+	data := []byte(`<doc><a>x &amp; y<![CDATA[ <z> ]]>&lt;w&gt;</a><b k="v">1<!-- c -->2</b><c xml:space="preserve"> <![CDATA[ ]]></c></doc>`)
+	m, err := NewMapXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.a"); v != "x & y <z> <w>" {
+		t.Fatalf("doc.a: %q", v)
+	}
+	if v, _ := m.ValueForPath("doc.b.#text"); v != "12" {
+		t.Fatalf("doc.b.#text: %q", v)
+	}
+	if v, _ := m.ValueForPath("doc.c.#text"); v != "  " {
+		t.Fatalf("doc.c.#text: %q", v)
+	}
+
+	// CDATA text with "]]>" is split across sections by XmlWithCDATA
+	m = Map{"doc": map[string]interface{}{"body": "a]]>b"}}
+	x, err := m.XmlWithCDATA([]string{"doc.body"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err = NewMapXml(x); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.ValueForPath("doc.body"); v != "a]]>b" {
+		t.Fatalf("doc.body: %q", v)
+	}
+}
+
+func TestXmlWrapArrays(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{
+		"item":  []interface{}{"a", map[string]interface{}{"item": []interface{}{"b", "c"}}},
+		"none":  []interface{}{},
+		"other": []interface{}{"x", "y"},
+		"one":   "z",
+	}}
+	wrap := map[string]string{"item": "items", "none": "nones", "one": "ones"}
+	x, err := m.XmlWrapArrays(wrap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc><items><item>a</item><item><items><item>b</item><item>c</item></items></item></items>` +
+		`<nones/><one>z</one><other>x</other><other>y</other></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	m = Map{"doc": map[string]interface{}{"item": []interface{}{"a", "b"}}}
+	x, err = m.Marshal(EncodeOptions{Indent: "  ", WrapArrays: wrap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "<doc>\n  <items>\n    <item>a</item>\n    <item>b</item>\n  </items>\n</doc>"
+	if string(x) != want {
+		t.Fatalf("got: %q want: %q", x, want)
+	}
+}
+
+func TestXmlMemberTags(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{
+		"tags":  []interface{}{"a", "b"},
+		"names": []string{"x"},
+		"none":  []interface{}{},
+		"other": []interface{}{"x", "y"},
+		"one":   "z",
+	}}
+	members := map[string]string{"tags": "tag", "names": "name", "none": "no", "one": "ones"}
+	x, err := m.XmlMemberTags(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<doc><names><name>x</name></names><none/><one>z</one><other>x</other><other>y</other>` +
+		`<tags><tag>a</tag><tag>b</tag></tags></doc>`
+	if string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	m = Map{"doc": map[string]interface{}{"tags": []interface{}{"a", "b"}}}
+	x, err = m.Marshal(EncodeOptions{Indent: "  ", MemberTags: members})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "<doc>\n  <tags>\n    <tag>a</tag>\n    <tag>b</tag>\n  </tags>\n</doc>"
+	if string(x) != want {
+		t.Fatalf("got: %q want: %q", x, want)
+	}
+}
+
+func TestXmlAttrFloatPrecision(t *testing.T) {
+	PrependAttrWithHyphen(true)
+	m := Map{"price": map[string]interface{}{"-currency": float64(1.5), "-big": 1e21, "#text": "EUR"}}
+
+	x, err := m.Xml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<price big="1000000000000000000000" currency="1.5">EUR</price>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	SetXmlAttrFloatPrecision(2)
+	defer SetXmlAttrFloatPrecision(0)
+	if x, err = m.XmlIndent("", "  "); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<price big="1000000000000000000000.00" currency="1.50">EUR</price>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	opts := EncodeOptions{AttrPrecision: 3}
+	if x, err = m.Marshal(opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<price big="1000000000000000000000.000" currency="1.500">EUR</price>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+}
+
+func TestXmlIndentSortedElements(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{
+		"zeta":  "1",
+		"alpha": []interface{}{"3", "2"},
+		"mid":   map[string]interface{}{"y": "a", "x": "b"},
+	}}
+	want := "<doc>\n  <alpha>3</alpha>\n  <alpha>2</alpha>\n  <mid>\n    <x>b</x>\n    <y>a</y>\n  </mid>\n  <zeta>1</zeta>\n</doc>"
+	for i := 0; i < 10; i++ {
+		x, err := m.XmlIndent("", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(x) != want {
+			t.Fatal("got:", string(x), "want:", want)
+		}
+	}
+}
+
+func TestXmlWithNilPolicy(t *testing.T) {
+	m := Map{"doc": map[string]interface{}{"a": nil, "b": "x", "c": []interface{}{nil, "y"}}}
+
+	x, err := m.XmlWithNilPolicy(EmptyNil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><a/><b>x</b><c/><c>y</c></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if x, err = m.XmlWithNilPolicy(OmitNil); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><b>x</b><c>y</c></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+	if x, err = m.XmlWithNilPolicy(XsiNil); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<doc><a xsi:nil="true"/><b>x</b><c xsi:nil="true"/><c>y</c></doc>`; string(x) != want {
+		t.Fatal("got:", string(x), "want:", want)
+	}
+
+	for policy, want := range map[NilPolicy]string{
+		OmitNil: "<doc>\n  <b>x</b>\n  <c>y</c>\n</doc>",
+		XsiNil:  "<doc>\n  <a xsi:nil=\"true\"/>\n  <b>x</b>\n  <c xsi:nil=\"true\"/>\n  <c>y</c>\n</doc>",
+	} {
+		if x, err = m.Marshal(EncodeOptions{Indent: "  ", NilPolicy: policy}); err != nil {
+			t.Fatal(err)
+		}
+		if string(x) != want {
+			t.Fatal("got:", string(x), "want:", want)
+		}
+	}
+}
@@ -112,6 +112,7 @@ func NewMapXmlSeqReader(xmlReader io.Reader, cast ...bool) (MapSeq, error) {
 	if _, ok := xmlReader.(io.ByteReader); !ok {
 		xmlReader = myByteReader(xmlReader) // see code at EOF
 	}
+	xmlReader = limitXmlReader(xmlReader) // per SetXmlReaderMaxMsgSize
 
 	// build the map
 	return xmlSeqReaderToMap(xmlReader, r)
@@ -143,7 +144,7 @@ func NewMapXmlSeqReaderRaw(xmlReader io.Reader, cast ...bool) (MapSeq, []byte, e
 	// create TeeReader so we can retrieve raw XML
 	buf := make([]byte, 0)
 	wb := bytes.NewBuffer(buf)
-	trdr := myTeeReader(xmlReader, wb)
+	trdr := limitXmlReader(myTeeReader(xmlReader, wb))
 
 	m, err := xmlSeqReaderToMap(trdr, r)
 
@@ -230,6 +231,9 @@ func xmlSeqToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[s
 	for {
 		t, err := p.RawToken()
 		if err != nil {
+			if err == XmlMsgTooLargeError {
+				return nil, err
+			}
 			if err != io.EOF {
 				return nil, errors.New("xml.Decoder.Token() - " + err.Error())
 			}
@@ -404,6 +408,8 @@ func xmlSeqToMapParser(skey string, a []xml.Attr, p *xml.Decoder, r bool) (map[s
 // Xml encodes a MapSeq as XML with elements sorted on #seq.  The companion of NewMapXmlSeq().
 // The following rules apply.
 //    - The "#seq" key value is used to seqence the subelements or attributes only.
+//    - Attributes are encoded before the subelements. Attributes and subelements without a
+//      "#seq" value - e.g., added to the MapSeq - follow the sequenced ones, in key order.
 //    - The "#attr" map key identifies the map of attribute map[string]interface{} values with "#text" key.
 //    - The "#comment" map key identifies a comment in the value "#text" map entry - <!--comment-->.
 //    - The "#directive" map key identifies a directive in the value "#text" map entry - <!directive>.
@@ -573,7 +579,8 @@ func mapToXmlSeqIndent(doIndent bool, s *string, key string, value interface{},
 	var noEndTag bool
 	var elen int
 	var ss string
-	p := &pretty{pp.indent, pp.cnt, pp.padding, pp.mapDepth, pp.start}
+	pc := *pp
+	p := &pc
 
 	switch value.(type) {
 	case map[string]interface{}, []byte, string, float64, bool, int, int32, int64, float32:
@@ -618,24 +625,28 @@ func mapToXmlSeqIndent(doIndent bool, s *string, key string, value interface{},
 				n++
 			}
 			sort.Sort(elemListSeq(kv))
-			// Now encode the attributes in original decoding sequence, using keyval array.
+			// Now encode the attributes in original decoding sequence, using keyval array;
+			// attributes without a "#seq" value follow, in key order.
 			for _, a := range kv {
-				vv := a.v.(map[string]interface{})
-				switch vv["#text"].(type) {
+				av := a.v
+				if vv, ok := av.(map[string]interface{}); ok {
+					av = vv["#text"]
+				}
+				switch av.(type) {
 				case string:
 					if xmlEscapeChars {
-						ss = escapeChars(vv["#text"].(string))
+						ss = escapeChars(av.(string))
 					} else {
-						ss = vv["#text"].(string)
+						ss = av.(string)
 					}
 					*s += ` ` + a.k + `="` + ss + `"`
 				case float64, bool, int, int32, int64, float32:
-					*s += ` ` + a.k + `="` + fmt.Sprintf("%v", vv["#text"]) + `"`
+					*s += ` ` + a.k + `="` + fmt.Sprintf("%v", av) + `"`
 				case []byte:
 					if xmlEscapeChars {
-						ss = escapeChars(string(vv["#text"].([]byte)))
+						ss = escapeChars(string(av.([]byte)))
 					} else {
-						ss = string(vv["#text"].([]byte))
+						ss = string(av.([]byte))
 					}
 					*s += ` ` + a.k + `="` + ss + `"`
 				default:
@@ -646,9 +657,16 @@ func mapToXmlSeqIndent(doIndent bool, s *string, key string, value interface{},
 		}
 
 		// simple element?
-		// every map value has, at least, "#seq" and, perhaps, "#text" and/or "#attr"
-		_, seqOK := val["#seq"] // have key
-		if v, ok := val["#text"]; ok && ((len(val) == 3 && haveAttrs) || (len(val) == 2 && !haveAttrs)) && seqOK {
+		// every decoded map value has "#seq" and, perhaps, "#text" and/or "#attr";
+		// values added to the MapSeq may not have "#seq"
+		n := len(val)
+		if _, seqOK := val["#seq"]; seqOK {
+			n--
+		}
+		if haveAttrs {
+			n--
+		}
+		if v, ok := val["#text"]; ok && n == 1 {
 			if stmp, ok := v.(string); ok && stmp != "" {
 				if xmlEscapeChars {
 					stmp = escapeChars(stmp)
@@ -659,7 +677,7 @@ func mapToXmlSeqIndent(doIndent bool, s *string, key string, value interface{},
 			}
 			isSimple = true
 			break
-		} else if !ok && ((len(val) == 2 && haveAttrs) || (len(val) == 1 && !haveAttrs)) && seqOK {
+		} else if !ok && n == 0 {
 			// here no #text but have #seq or #seq+#attr
 			endTag = false
 			break
@@ -693,7 +711,7 @@ func mapToXmlSeqIndent(doIndent bool, s *string, key string, value interface{},
 		}
 		// something more complex
 		p.mapDepth++
-		sort.Sort(elemListSeq(kv))
+		sort.Stable(elemListSeq(kv))
 		i := 0
 		for _, v := range kv {
 			switch v.v.(type) {
@@ -841,27 +859,27 @@ func (e elemListSeq) Swap(i, j int) {
 	e[i], e[j] = e[j], e[i]
 }
 
+// Less orders the entries on their "#seq" values; entries without one - e.g., values that
+// were added to the MapSeq - follow, and ties are ordered on the key, so the encoding is
+// deterministic. (With sort.Stable, list members with the same key keep their order.)
 func (e elemListSeq) Less(i, j int) bool {
-	var iseq, jseq int
-	var fiseq, fjseq float64
-	var ok bool
-	if iseq, ok = e[i].v.(map[string]interface{})["#seq"].(int); !ok {
-		if fiseq, ok = e[i].v.(map[string]interface{})["#seq"].(float64); ok {
-			iseq = int(fiseq)
-		} else {
-			iseq = 9999999
-		}
+	iseq, jseq := seqNum(e[i].v), seqNum(e[j].v)
+	if iseq != jseq {
+		return iseq < jseq
 	}
+	return e[i].k < e[j].k
+}
 
-	if jseq, ok = e[j].v.(map[string]interface{})["#seq"].(int); !ok {
-		if fjseq, ok = e[j].v.(map[string]interface{})["#seq"].(float64); ok {
-			jseq = int(fjseq)
-		} else {
-			jseq = 9999999
-		}
+// seqNum returns the "#seq" value of 'v', or 9999999 if it doesn't have one.
+func seqNum(v interface{}) int {
+	m, _ := v.(map[string]interface{})
+	switch n := m["#seq"].(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
 	}
-
-	return iseq <= jseq
+	return 9999999
 }
 
 // =============== https://groups.google.com/forum/#!topic/golang-nuts/lHPOHD-8qio
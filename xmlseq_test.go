@@ -78,3 +78,38 @@ func TestXmlSeqDecodeError(t *testing.T) {
 	}
 	fmt.Println("err ok:", err)
 }
+
+func TestXmlSeqAttrsFirst(t *testing.T) {
+	x := []byte(`<doc z="1" a="2"><c>1</c><b id="x">2</b><c>3</c><a/></doc>`)
+	m, err := NewMapXmlSeq(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// added values, without "#seq", follow in key order
+	doc := m["doc"].(map[string]interface{})
+	doc["#attr"].(map[string]interface{})["m"] = "3"
+	doc["#attr"].(map[string]interface{})["b"] = map[string]interface{}{"#text": "4"}
+	doc["y"] = "5"
+	doc["e"] = map[string]interface{}{"#attr": map[string]interface{}{"k": "v"}}
+
+	want := `<doc z="1" a="2" b="4" m="3">
+  <c>1</c>
+  <b id="x">2</b>
+  <c>3</c>
+  <a/>
+  <e k="v"/>
+  <y>5</y>
+</doc>`
+	for i := 0; i < 10; i++ {
+		b, err := m.XmlIndent("", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Fatal("got:\n", string(b), "\nwant:\n", want)
+		}
+		if _, err = NewMapXml(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
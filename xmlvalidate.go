@@ -0,0 +1,189 @@
+// xmlvalidate.go - check that a Map encodes as a well-formed XML document.
+
+package mxj
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// XmlValidate checks that mv.Xml(rootTag...) encodes the Map as a single-root, well-formed
+// XML document without changing its content, and returns an error describing the first
+// problem found, if any. It is an error if:
+//	- there is no 'rootTag' and the Map doesn't have exactly one key, or the key is an
+//	  attribute or "#text", or the value is a list - mv.Xml() would encode the values in a
+//	  DefaultRootTag element or as several root elements;
+//	- a key is not a valid XML name - e.g., "1st" or "a b" - after the attribute prefix;
+//	- a "#text" or attribute value is not a simple value - string, number, boolean;
+//	- a value is a map that isn't a map[string]interface{} - its keys would be coerced
+//	  to strings - or a list has a list member;
+//	- a string value has characters that aren't allowed in XML, or has '<' or '&' and
+//	  XMLEscapeChars() isn't set.
+// The value types that mv.Xml() handles with xml.Marshal() or fmt.Sprint() are not checked.
+func (mv Map) XmlValidate(rootTag ...string) error {
+	return mv.xmlValidate(new(pretty).escapeChars(), rootTag...)
+}
+
+// xmlValidate is mv.XmlValidate() with values escaped per 'escape'.
+func (mv Map) xmlValidate(escape bool, rootTag ...string) error {
+	v := &xmlValidator{escape: escape}
+	if len(rootTag) == 1 {
+		if !isXmlName(rootTag[0]) {
+			return fmt.Errorf("XmlValidate: rootTag is not a valid XML name: %q", rootTag[0])
+		}
+		return v.elem(rootTag[0], map[string]interface{}(mv))
+	}
+	if len(mv) != 1 {
+		return fmt.Errorf("XmlValidate: Map has %d keys, want one root key or a rootTag", len(mv))
+	}
+	for k, val := range mv {
+		if k == "#text" || k == "#attr" || lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix) {
+			return fmt.Errorf("XmlValidate: root key is not an element: %s", k)
+		}
+		if _, ok := val.([]interface{}); ok {
+			return fmt.Errorf("XmlValidate: root value is a list: %s", k)
+		}
+		return v.elem(k, val)
+	}
+	return nil
+}
+
+// xmlValidator is the state for mv.XmlValidate().
+type xmlValidator struct {
+	escape bool // per XMLEscapeChars
+}
+
+// elem checks the element 'path' with value 'val'.
+func (v *xmlValidator) elem(path string, val interface{}) error {
+	switch val.(type) {
+	case map[string]interface{}:
+		// the keys are sorted, as for mv.Xml(), so the first problem found is the same for every call
+		m := val.(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			vv := m[k]
+			p := path + "." + k
+			switch {
+			case k == "#text":
+				if err := v.simple(p, vv); err != nil {
+					return err
+				}
+			case k == "#attr":
+				if am, ok := vv.(map[string]interface{}); ok {
+					akeys := make([]string, 0, len(am))
+					for ak := range am {
+						akeys = append(akeys, ak)
+					}
+					sort.Strings(akeys)
+					for _, ak := range akeys {
+						av := am[ak]
+						if !isXmlName(ak) {
+							return fmt.Errorf("XmlValidate: %s.%s: key is not a valid XML name", p, ak)
+						}
+						if err := v.simple(p+"."+ak, av); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				fallthrough
+			case lenAttrPrefix > 0 && strings.HasPrefix(k, attrPrefix):
+				if !isXmlName(strings.TrimPrefix(k, attrPrefix)) {
+					return fmt.Errorf("XmlValidate: %s: key is not a valid XML name", p)
+				}
+				if err := v.simple(p, vv); err != nil {
+					return err
+				}
+			default:
+				if !isXmlName(k) {
+					return fmt.Errorf("XmlValidate: %s: key is not a valid XML name", p)
+				}
+				if err := v.elem(p, vv); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		for i, vv := range val.([]interface{}) {
+			if _, ok := vv.([]interface{}); ok {
+				return fmt.Errorf("XmlValidate: %s[%d]: list member is a list", path, i)
+			}
+			if err := v.elem(fmt.Sprintf("%s[%d]", path, i), vv); err != nil {
+				return err
+			}
+		}
+	case string:
+		return v.text(path, val.(string))
+	case []byte:
+		return v.text(path, string(val.([]byte)))
+	default:
+		if val != nil && reflect.ValueOf(val).Kind() == reflect.Map {
+			return fmt.Errorf("XmlValidate: %s: map value is not a map[string]interface{}: %T", path, val)
+		}
+	}
+	return nil
+}
+
+// simple checks a "#text" or attribute value.
+func (v *xmlValidator) simple(path string, val interface{}) error {
+	switch val.(type) {
+	case string:
+		return v.text(path, val.(string))
+	case []byte:
+		return v.text(path, string(val.([]byte)))
+	case float64, float32, bool, int, int32, int64, json.Number:
+		return nil
+	}
+	return fmt.Errorf("XmlValidate: %s: value is not a simple value: %T", path, val)
+}
+
+// text checks that 's' encodes as valid XML character data.
+func (v *xmlValidator) text(path, s string) error {
+	if !v.escape && strings.ContainsAny(s, "<&") {
+		return fmt.Errorf("XmlValidate: %s: value has '<' or '&' and XMLEscapeChars is not set", path)
+	}
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && w == 1 {
+			return fmt.Errorf("XmlValidate: %s: value has an invalid UTF-8 byte at #%d: %#x", path, i, s[i])
+		}
+		if !isXmlChar(r) {
+			return fmt.Errorf("XmlValidate: %s: value has an invalid XML character at #%d: %U", path, i, r)
+		}
+		i += w
+	}
+	return nil
+}
+
+// isXmlChar reports whether 'r' is an XML 1.0 Char.
+func isXmlChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+// isXmlName reports whether 's' is a valid XML element or attribute name.
+func isXmlName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r == ':' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package mxj
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestXmlValidate(t *testing.T) {
+	fmt.Println("\n------------ xmlvalidate_test.go")
+	PrependAttrWithHyphen(true)
+
+	m := Map{"doc": map[string]interface{}{
+		"-id":   1,
+		"title": "text",
+		"item":  []interface{}{"a", map[string]interface{}{"#text": "b", "-seq": "2"}},
+		"empty": nil,
+		"#attr": map[string]interface{}{"xml:lang": "en"},
+	}}
+	if err := m.XmlValidate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Map{"a": "1", "b": "2"}).XmlValidate("root"); err != nil {
+		t.Fatal(err)
+	}
+
+	// U+FFFD is a valid character
+	if err := (Map{"doc": "x\uFFFDy"}).XmlValidate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range []struct {
+		m   Map
+		err string
+	}{
+		{Map{"a": "1", "b": "2"}, "Map has 2 keys, want one root key or a rootTag"},
+		{Map{}, "Map has 0 keys, want one root key or a rootTag"},
+		{Map{"-a": "1"}, "root key is not an element: -a"},
+		{Map{"a": []interface{}{map[string]interface{}{}, map[string]interface{}{}}}, "root value is a list: a"},
+		{Map{"doc": map[string]interface{}{"1st": "x"}}, "doc.1st: key is not a valid XML name"},
+		{Map{"doc": map[string]interface{}{"a b": "x"}}, "doc.a b: key is not a valid XML name"},
+		{Map{"doc": map[string]interface{}{"-a b": "x"}}, "doc.-a b: key is not a valid XML name"},
+		{Map{"doc": map[string]interface{}{"-a": []interface{}{"x"}}}, "doc.-a: value is not a simple value: []interface {}"},
+		{Map{"doc": map[string]interface{}{"#text": map[string]interface{}{}}}, "doc.#text: value is not a simple value: map[string]interface {}"},
+		{Map{"doc": map[string]interface{}{"a": map[int]interface{}{1: "x"}}}, "doc.a: map value is not a map[string]interface{}: map[int]interface {}"},
+		{Map{"doc": map[string]interface{}{"a": []interface{}{[]interface{}{"x"}}}}, "doc.a[0]: list member is a list"},
+		{Map{"doc": map[string]interface{}{"a": "x\x01y"}}, "doc.a: value has an invalid XML character at #1: U+0001"},
+		{Map{"doc": map[string]interface{}{"a": "x\xffy"}}, "doc.a: value has an invalid UTF-8 byte at #1: 0xff"},
+		{Map{"doc": map[string]interface{}{"a": "x & y"}}, "doc.a: value has '<' or '&' and XMLEscapeChars is not set"},
+	} {
+		err := bad.m.XmlValidate()
+		if err == nil {
+			t.Fatal("no error for:", bad.m)
+		}
+		if want := "XmlValidate: " + bad.err; err.Error() != want {
+			t.Fatal("got:", err, "want:", want)
+		}
+	}
+
+	// with several problems, the same one is reported every time
+	bad := Map{"doc": map[string]interface{}{"b": "x\x01", "a b": "1", "c": map[int]interface{}{}, "#attr": map[string]interface{}{"z": "\x02", "y y": "1"}}}
+	for i := 0; i < 20; i++ {
+		if err := bad.XmlValidate(); err == nil || err.Error() != "XmlValidate: doc.#attr.y y: key is not a valid XML name" {
+			t.Fatal("got:", err)
+		}
+	}
+
+	XMLEscapeChars(true)
+	defer XMLEscapeChars(false)
+	if err := (Map{"doc": "x & y"}).XmlValidate(); err != nil {
+		t.Fatal(err)
+	}
+}